@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PeerInfo describes a single Cassandra node as seen from system.local /
+// system.peers, mirroring the fields navigator's upgrade coordinator tracks
+// per node.
+type PeerInfo struct {
+	Host    string `json:"host"`
+	DC      string `json:"dc"`
+	Rack    string `json:"rack"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// ClusterMonitor periodically refreshes the release_version of every node
+// in the cluster so callers can detect an in-progress rolling upgrade.
+type ClusterMonitor struct {
+	cm         *CassandraManager
+	minVersion string
+
+	mu    sync.RWMutex
+	peers []PeerInfo
+}
+
+func NewClusterMonitor(cm *CassandraManager, minVersion string) *ClusterMonitor {
+	return &ClusterMonitor{cm: cm, minVersion: minVersion}
+}
+
+// Start runs one refresh, then launches the background refresh loop and
+// returns; the loop stops once ctx is cancelled.
+func (m *ClusterMonitor) Start(ctx context.Context, interval time.Duration) {
+	if err := m.refresh(); err != nil {
+		log.Printf("Initial cluster version refresh failed: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.refresh(); err != nil {
+					log.Printf("Cluster version refresh failed: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (m *ClusterMonitor) refresh() error {
+	session := m.cm.session
+	if session == nil {
+		return fmt.Errorf("session not initialized")
+	}
+
+	var peers []PeerInfo
+
+	var localVersion string
+	if err := session.Query("SELECT release_version FROM system.local").Scan(&localVersion); err != nil {
+		return fmt.Errorf("failed to read system.local: %v", err)
+	}
+	peers = append(peers, PeerInfo{
+		Host:    m.cm.config.CassandraHost,
+		DC:      m.cm.config.Datacenter,
+		Version: localVersion,
+		Status:  "up",
+	})
+
+	iter := session.Query("SELECT peer, data_center, rack, release_version FROM system.peers").Iter()
+	var peer, dc, rack, version string
+	for iter.Scan(&peer, &dc, &rack, &version) {
+		peers = append(peers, PeerInfo{
+			Host:    peer,
+			DC:      dc,
+			Rack:    rack,
+			Version: version,
+			Status:  "up",
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read system.peers: %v", err)
+	}
+
+	m.mu.Lock()
+	m.peers = peers
+	m.mu.Unlock()
+	return nil
+}
+
+// Nodes returns the last known state of every node in the cluster.
+func (m *ClusterMonitor) Nodes() []PeerInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	nodes := make([]PeerInfo, len(m.peers))
+	copy(nodes, m.peers)
+	return nodes
+}
+
+// EffectiveVersion returns the lowest release_version across the cluster,
+// matching navigator's NodePool.Status.Version semantics: while an upgrade
+// is in progress the oldest node is the one that gates feature usage.
+func (m *ClusterMonitor) EffectiveVersion() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lowest := ""
+	for _, peer := range m.peers {
+		if peer.Version == "" {
+			continue
+		}
+		if lowest == "" || compareVersions(peer.Version, lowest) < 0 {
+			lowest = peer.Version
+		}
+	}
+	return lowest
+}
+
+// MeetsMinimum reports whether the cluster's effective version is at or
+// above min. An empty effective version (no data yet) is treated as meeting
+// the minimum so the gate doesn't fail closed before the first refresh.
+func (m *ClusterMonitor) MeetsMinimum(min string) bool {
+	if min == "" {
+		return true
+	}
+	effective := m.EffectiveVersion()
+	if effective == "" {
+		return true
+	}
+	return compareVersions(effective, min) >= 0
+}
+
+// compareVersions compares two dotted numeric version strings (e.g.
+// "4.0.10" vs "4.1.0"), returning -1, 0, or 1. Non-numeric components
+// compare as 0 so a malformed version doesn't panic.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var av, bv int
+		if i < len(aParts) {
+			av, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bv, _ = strconv.Atoi(bParts[i])
+		}
+		if av != bv {
+			if av < bv {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func (m *ClusterMonitor) clusterNodesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.Nodes())
+}
+
+func (m *ClusterMonitor) clusterVersionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"version": m.EffectiveVersion()})
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"4.0.10", "4.1.0", -1},
+		{"4.1.0", "4.0.10", 1},
+		{"4.0.10", "4.0.10", 0},
+		{"4.0", "4.0.0", 0},
+		{"5.0", "4.9.9", 1},
+		{"4.0.x", "4.0.0", 0}, // non-numeric component compares as 0
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClusterMonitorEffectiveVersion(t *testing.T) {
+	m := &ClusterMonitor{
+		peers: []PeerInfo{
+			{Host: "a", Version: "4.1.0"},
+			{Host: "b", Version: "4.0.10"},
+			{Host: "c", Version: ""}, // no version yet, ignored
+		},
+	}
+
+	if got := m.EffectiveVersion(); got != "4.0.10" {
+		t.Errorf("EffectiveVersion() = %q, want %q (the lowest reporting peer)", got, "4.0.10")
+	}
+}
+
+func TestClusterMonitorMeetsMinimum(t *testing.T) {
+	tests := []struct {
+		name  string
+		peers []PeerInfo
+		min   string
+		want  bool
+	}{
+		{
+			name: "no minimum configured",
+			min:  "",
+			want: true,
+		},
+		{
+			name: "no data yet",
+			min:  "4.0.0",
+			want: true,
+		},
+		{
+			name:  "cluster meets minimum",
+			peers: []PeerInfo{{Version: "4.1.0"}},
+			min:   "4.0.0",
+			want:  true,
+		},
+		{
+			name:  "cluster below minimum during mixed-version upgrade",
+			peers: []PeerInfo{{Version: "4.1.0"}, {Version: "3.11.9"}},
+			min:   "4.0.0",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ClusterMonitor{minVersion: tt.min, peers: tt.peers}
+			if got := m.MeetsMinimum(tt.min); got != tt.want {
+				t.Errorf("MeetsMinimum(%q) = %v, want %v", tt.min, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"compress/gzip"
+	"net/http"
+)
+
+// decompressRequestBody transparently gunzips incoming request bodies sent
+// with Content-Encoding: gzip (as the frontend's postJSON does once a
+// payload crosses its compression threshold), so handlers can keep decoding
+// JSON off r.Body without knowing whether the client compressed it.
+func decompressRequestBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid gzip request body", http.StatusBadRequest)
+			return
+		}
+		defer reader.Close()
+
+		r.Body = reader
+		r.Header.Del("Content-Encoding")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+	})
+}
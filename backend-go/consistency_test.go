@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestParseConsistency(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    gocql.Consistency
+		wantErr bool
+	}{
+		{name: "one", want: gocql.One},
+		{name: "ONE", want: gocql.One},
+		{name: "quorum", want: gocql.Quorum},
+		{name: "local_quorum", want: gocql.LocalQuorum},
+		{name: "all", want: gocql.All},
+		{name: "bogus", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseConsistency(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseConsistency(%q) = %v, want error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseConsistency(%q) returned unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseConsistency(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConsistencyFromRequest(t *testing.T) {
+	t.Run("query parameter wins", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/jobs/1?consistency=all", nil)
+		r.Header.Set("X-Consistency", "one")
+
+		got, err := consistencyFromRequest(r, gocql.Quorum)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != gocql.All {
+			t.Errorf("got %v, want %v", got, gocql.All)
+		}
+	})
+
+	t.Run("header used when no query parameter", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/jobs/1", nil)
+		r.Header.Set("X-Consistency", "local_quorum")
+
+		got, err := consistencyFromRequest(r, gocql.Quorum)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != gocql.LocalQuorum {
+			t.Errorf("got %v, want %v", got, gocql.LocalQuorum)
+		}
+	})
+
+	t.Run("falls back to default when neither is set", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/jobs/1", nil)
+
+		got, err := consistencyFromRequest(r, gocql.Quorum)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != gocql.Quorum {
+			t.Errorf("got %v, want %v", got, gocql.Quorum)
+		}
+	})
+
+	t.Run("invalid level is rejected", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/jobs/1?consistency=bogus", nil)
+
+		if _, err := consistencyFromRequest(r, gocql.Quorum); err == nil {
+			t.Error("expected an error for an unknown consistency level")
+		}
+	})
+}
@@ -2,26 +2,52 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+
+	"backend-go/scheduler"
 )
 
 // Configuration
 type Config struct {
-	CassandraHost string
-	Keyspace     string
-	Datacenter   string
-	Port         string
+	CassandraHost  string
+	CassandraHosts []string
+	Keyspace       string
+	Datacenter     string
+	LocalDC        string
+	Port           string
+	AdminSecret    string
+
+	Username string
+	Password string
+
+	TLSCA         string
+	TLSCert       string
+	TLSKey        string
+	TLSServerName string
+
+	ProtoVersion   int
+	Consistency    gocql.Consistency
+	ConnectTimeout time.Duration
+
+	MinCassandraVersion string
 }
 
 // Job model
@@ -34,6 +60,14 @@ type Job struct {
 	Priority    int       `json:"priority"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Schedule columns added by migration 0002. The create-job worker writes
+	// schedule_id on jobs a schedule spawns; run_at and lease_until are not
+	// written. The job queries in this file do not select these columns, so
+	// the fields stay empty in job responses.
+	ScheduleID string     `json:"schedule_id,omitempty"`
+	RunAt      *time.Time `json:"run_at,omitempty"`
+	LeaseUntil *time.Time `json:"lease_until,omitempty"`
 }
 
 // Response models
@@ -51,10 +85,11 @@ type ClusterInfo struct {
 }
 
 type JobsResponse struct {
-	Jobs   []Job `json:"jobs"`
-	Total  int   `json:"total"`
-	Pod    string `json:"pod"`
-	Language string `json:"language"`
+	Jobs          []Job  `json:"jobs"`
+	Total         int    `json:"total"`
+	Pod           string `json:"pod"`
+	Language      string `json:"language"`
+	NextPageState string `json:"next_page_state,omitempty"`
 }
 
 type HealthResponse struct {
@@ -89,13 +124,103 @@ type SuccessResponse struct {
 	Language string `json:"language"`
 }
 
+// CreateJobResponse is SuccessResponse plus the ID of the job that was just
+// created, so a caller can link straight to its detail page.
+type CreateJobResponse struct {
+	Message  string `json:"message"`
+	Pod      string `json:"pod"`
+	Language string `json:"language"`
+	ID       string `json:"id"`
+}
+
+// createJobRequest is the body accepted by both createJobHandler and
+// validateJobHandler, so the two endpoints stay in lockstep.
+type createJobRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	AssignedTo  string `json:"assigned_to"`
+	Priority    int    `json:"priority"`
+}
+
+const (
+	jobTitleMaxLen       = 200
+	jobDescriptionMaxLen = 2000
+)
+
+// validJobStatuses are the statuses the scheduler and sample data already
+// use (pending, in_progress) plus completed; anything else is rejected
+// rather than silently stored.
+var validJobStatuses = map[string]bool{
+	"pending":     true,
+	"in_progress": true,
+	"completed":   true,
+}
+
+// validateJobRequest checks req against the rules createJobHandler enforces
+// before inserting, returning a field->message map of everything wrong. A
+// zero-value Status or Priority is left alone here; applyJobDefaults fills
+// those in once validation passes.
+func validateJobRequest(req *createJobRequest) map[string]string {
+	errs := make(map[string]string)
+
+	if strings.TrimSpace(req.Title) == "" {
+		errs["title"] = "Title is required"
+	} else if len(req.Title) > jobTitleMaxLen {
+		errs["title"] = fmt.Sprintf("Title must be %d characters or fewer", jobTitleMaxLen)
+	}
+
+	if strings.TrimSpace(req.Description) == "" {
+		errs["description"] = "Description is required"
+	} else if len(req.Description) > jobDescriptionMaxLen {
+		errs["description"] = fmt.Sprintf("Description must be %d characters or fewer", jobDescriptionMaxLen)
+	}
+
+	if req.Status != "" && !validJobStatuses[req.Status] {
+		errs["status"] = "Status must be one of pending, in_progress, completed"
+	}
+
+	if req.Priority != 0 && (req.Priority < 1 || req.Priority > 5) {
+		errs["priority"] = "Priority must be between 1 and 5"
+	}
+
+	return errs
+}
+
+// applyJobDefaults fills in the optional fields createJobHandler has always
+// defaulted; call it only after validateJobRequest reports no errors.
+func applyJobDefaults(req *createJobRequest) {
+	if req.Status == "" {
+		req.Status = "pending"
+	}
+	if req.AssignedTo == "" {
+		req.AssignedTo = "unassigned"
+	}
+	if req.Priority == 0 {
+		req.Priority = 1
+	}
+}
+
 // Cassandra manager
 type CassandraManager struct {
 	cluster *gocql.ClusterConfig
 	session *gocql.Session
 	config  *Config
+
+	clusterMonitor *ClusterMonitor
 }
 
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+
+	// Held as prepared query templates: gocql caches the prepared statement
+	// for a given CQL string on the session, so reusing these constants
+	// across requests avoids re-parsing CQL on every call.
+	selectJobsPageCQL   = "SELECT id, title, description, status, created_at, updated_at, assigned_to, priority FROM jobs"
+	selectJobByTokenCQL = "SELECT id, title, description, status, created_at, updated_at, assigned_to, priority FROM jobs WHERE token(id) >= ? LIMIT 1"
+)
+
 func NewCassandraManager(config *Config) *CassandraManager {
 	return &CassandraManager{
 		config: config,
@@ -103,140 +228,265 @@ func NewCassandraManager(config *Config) *CassandraManager {
 }
 
 func (cm *CassandraManager) Connect() error {
-	cluster := gocql.NewCluster(cm.config.CassandraHost)
+	hosts := cm.config.CassandraHosts
+	if len(hosts) == 0 {
+		hosts = []string{cm.config.CassandraHost}
+	}
+
+	cluster := gocql.NewCluster(hosts...)
 	cluster.Keyspace = cm.config.Keyspace
-	cluster.Consistency = gocql.Quorum
-	cluster.ConnectTimeout = 30 * time.Second
-	
+	cluster.Consistency = cm.config.Consistency
+	cluster.ConnectTimeout = cm.config.ConnectTimeout
+
+	if cm.config.ProtoVersion != 0 {
+		cluster.ProtoVersion = cm.config.ProtoVersion
+	}
+
+	if cm.config.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: cm.config.Username,
+			Password: cm.config.Password,
+		}
+	}
+
+	if cm.config.TLSCA != "" || cm.config.TLSCert != "" {
+		tlsConfig, err := buildTLSConfig(cm.config)
+		if err != nil {
+			return fmt.Errorf("failed to build TLS config: %v", err)
+		}
+		cluster.SslOpts = &gocql.SslOptions{
+			Config:                 tlsConfig,
+			EnableHostVerification: cm.config.TLSServerName != "",
+		}
+	}
+
+	if cm.config.LocalDC != "" {
+		cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(
+			gocql.DCAwareRoundRobinPolicy(cm.config.LocalDC),
+		)
+	}
+
 	session, err := cluster.CreateSession()
 	if err != nil {
 		return fmt.Errorf("failed to connect to Cassandra: %v", err)
 	}
-	
+
 	cm.session = session
 	cm.cluster = cluster
-	log.Printf("Connected to Cassandra cluster at %s", cm.config.CassandraHost)
+	log.Printf("Connected to Cassandra cluster at %v", hosts)
 	return nil
 }
 
-func (cm *CassandraManager) InitializeSchema() error {
+// buildTLSConfig assembles a tls.Config from the configured CA/cert/key PEM
+// file paths, failing fast with a descriptive error so misconfiguration
+// surfaces in pod logs instead of as an opaque handshake failure.
+func buildTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: config.TLSServerName,
+	}
+
+	if config.TLSCA != "" {
+		caPEM, err := os.ReadFile(config.TLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CASSANDRA_TLS_CA %q: %v", config.TLSCA, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("CASSANDRA_TLS_CA %q does not contain a valid PEM certificate", config.TLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.TLSCert != "" || config.TLSKey != "" {
+		if config.TLSCert == "" || config.TLSKey == "" {
+			return nil, fmt.Errorf("CASSANDRA_TLS_CERT and CASSANDRA_TLS_KEY must both be set")
+		}
+		cert, err := tls.LoadX509KeyPair(config.TLSCert, config.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate/key pair: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// EnsureKeyspace creates the configured keyspace if it doesn't already
+// exist. Table creation itself is owned by the Migrator.
+func (cm *CassandraManager) EnsureKeyspace() error {
 	if cm.session == nil {
 		return fmt.Errorf("session not initialized")
 	}
 
-	// Create keyspace
 	err := cm.session.Query(fmt.Sprintf(`
-		CREATE KEYSPACE IF NOT EXISTS %s 
+		CREATE KEYSPACE IF NOT EXISTS %s
 		WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 3}
 	`, cm.config.Keyspace)).Exec()
 	if err != nil {
 		return fmt.Errorf("failed to create keyspace: %v", err)
 	}
 
-	// Create jobs table
-	err = cm.session.Query(`
-		CREATE TABLE IF NOT EXISTS jobs (
-			id UUID PRIMARY KEY,
-			title TEXT,
-			description TEXT,
-			status TEXT,
-			created_at TIMESTAMP,
-			updated_at TIMESTAMP,
-			assigned_to TEXT,
-			priority INT
-		)
-	`).Exec()
-	if err != nil {
-		return fmt.Errorf("failed to create jobs table: %v", err)
+	return nil
+}
+
+// SeedSampleJobs inserts the demo dataset when the jobs table is empty. It
+// is safe to call on every boot.
+func (cm *CassandraManager) SeedSampleJobs() error {
+	if cm.session == nil {
+		return fmt.Errorf("session not initialized")
 	}
 
-	// Insert sample data if table is empty
 	var count int
-	err = cm.session.Query("SELECT COUNT(*) FROM jobs").Consistency(gocql.One).Scan(&count)
+	err := cm.session.Query("SELECT COUNT(*) FROM jobs").Consistency(gocql.One).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("failed to count jobs: %v", err)
 	}
+	if count > 0 {
+		return nil
+	}
 
-	if count == 0 {
-		sampleJobs := []struct {
-			Title       string
-			Description string
-			Status      string
-			Priority    int
-		}{
-			{"Database Migration", "Migrate database to latest version", "pending", 1},
-			{"API Development", "Develop REST API endpoints", "in_progress", 2},
-			{"Testing Suite", "Create comprehensive test suite", "pending", 3},
-			{"Documentation", "Write technical documentation", "pending", 4},
-			{"Performance Optimization", "Optimize application performance", "pending", 5},
-		}
+	sampleJobs := []struct {
+		Title       string
+		Description string
+		Status      string
+		Priority    int
+	}{
+		{"Database Migration", "Migrate database to latest version", "pending", 1},
+		{"API Development", "Develop REST API endpoints", "in_progress", 2},
+		{"Testing Suite", "Create comprehensive test suite", "pending", 3},
+		{"Documentation", "Write technical documentation", "pending", 4},
+		{"Performance Optimization", "Optimize application performance", "pending", 5},
+	}
 
-		for _, job := range sampleJobs {
-			err := cm.session.Query(`
-				INSERT INTO jobs (id, title, description, status, created_at, updated_at, assigned_to, priority)
-				VALUES (uuid(), ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?, ?)
-			`, job.Title, job.Description, job.Status, "unassigned", job.Priority).Exec()
-			if err != nil {
-				return fmt.Errorf("failed to insert sample job: %v", err)
-			}
+	for _, job := range sampleJobs {
+		err := cm.session.Query(`
+			INSERT INTO jobs (id, title, description, status, created_at, updated_at, assigned_to, priority)
+			VALUES (uuid(), ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?, ?)
+		`, job.Title, job.Description, job.Status, "unassigned", job.Priority).Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert sample job: %v", err)
 		}
-		log.Println("Sample jobs inserted into Cassandra")
 	}
-
-	log.Println("Cassandra schema initialization completed")
+	log.Println("Sample jobs inserted into Cassandra")
 	return nil
 }
 
+// GetRandomJob picks a genuinely random row by sampling a random token and
+// scanning forward from it, rather than pulling several rows just to return
+// one.
 func (cm *CassandraManager) GetRandomJob() (*Job, error) {
 	if cm.session == nil {
 		return nil, fmt.Errorf("session not initialized")
 	}
 
+	token := int64(rand.Uint64())
+
 	var job Job
-	iter := cm.session.Query("SELECT * FROM jobs LIMIT 5").Iter()
-	defer iter.Close()
+	err := cm.session.Query(selectJobByTokenCQL, token).Scan(&job.ID, &job.Title, &job.Description,
+		&job.Status, &job.CreatedAt, &job.UpdatedAt, &job.AssignedTo, &job.Priority)
+	if err == gocql.ErrNotFound {
+		// Token ranges wrap around; retry from the start of the ring.
+		err = cm.session.Query(selectJobByTokenCQL, int64(math.MinInt64)).Scan(&job.ID, &job.Title,
+			&job.Description, &job.Status, &job.CreatedAt, &job.UpdatedAt, &job.AssignedTo, &job.Priority)
+	}
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample random job: %v", err)
+	}
+
+	return &job, nil
+}
+
+// GetAllJobs returns up to pageSize jobs starting from pageState (the
+// base64-decoded bytes of a previous iter.PageState()), along with the
+// page state to continue from on the next call.
+func (cm *CassandraManager) GetAllJobs(pageSize int, pageState []byte) ([]Job, []byte, error) {
+	if cm.session == nil {
+		return nil, nil, fmt.Errorf("session not initialized")
+	}
+
+	iter := cm.session.Query(selectJobsPageCQL).PageSize(pageSize).PageState(pageState).Iter()
 
 	var jobs []Job
-	for iter.Scan(&job.ID, &job.Title, &job.Description, &job.Status, 
+	var job Job
+	for iter.Scan(&job.ID, &job.Title, &job.Description, &job.Status,
 		&job.CreatedAt, &job.UpdatedAt, &job.AssignedTo, &job.Priority) {
 		jobs = append(jobs, job)
 	}
 
-	if len(jobs) == 0 {
-		return nil, nil
+	nextPageState := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch jobs page: %v", err)
 	}
 
-	// Return first job (simplified random selection)
-	return &jobs[0], nil
+	return jobs, nextPageState, nil
 }
 
-func (cm *CassandraManager) GetAllJobs() ([]Job, error) {
+func (cm *CassandraManager) CreateJob(title, description, status, assignedTo string, priority int) (gocql.UUID, error) {
 	if cm.session == nil {
-		return nil, fmt.Errorf("session not initialized")
+		return gocql.UUID{}, fmt.Errorf("session not initialized")
 	}
 
-	var jobs []Job
-	iter := cm.session.Query("SELECT * FROM jobs").Iter()
-	defer iter.Close()
+	id := gocql.TimeUUID()
+	err := cm.session.Query(`
+		INSERT INTO jobs (id, title, description, status, created_at, updated_at, assigned_to, priority)
+		VALUES (?, ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?, ?)
+	`, id, title, description, status, assignedTo, priority).Exec()
+	return id, err
+}
+
+func (cm *CassandraManager) GetJobByID(id gocql.UUID, consistency gocql.Consistency) (*Job, error) {
+	if cm.session == nil {
+		return nil, fmt.Errorf("session not initialized")
+	}
 
 	var job Job
-	for iter.Scan(&job.ID, &job.Title, &job.Description, &job.Status, 
-		&job.CreatedAt, &job.UpdatedAt, &job.AssignedTo, &job.Priority) {
-		jobs = append(jobs, job)
+	err := cm.session.Query(`
+		SELECT id, title, description, status, created_at, updated_at, assigned_to, priority
+		FROM jobs WHERE id = ?
+	`, id).Consistency(consistency).Scan(&job.ID, &job.Title, &job.Description, &job.Status,
+		&job.CreatedAt, &job.UpdatedAt, &job.AssignedTo, &job.Priority)
+
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job %s: %v", id, err)
 	}
 
-	return jobs, nil
+	return &job, nil
 }
 
-func (cm *CassandraManager) CreateJob(title, description, status, assignedTo string, priority int) error {
+func (cm *CassandraManager) UpdateJob(id gocql.UUID, fields map[string]interface{}, consistency gocql.Consistency) error {
 	if cm.session == nil {
 		return fmt.Errorf("session not initialized")
 	}
 
-	return cm.session.Query(`
-		INSERT INTO jobs (id, title, description, status, created_at, updated_at, assigned_to, priority)
-		VALUES (uuid(), ?, ?, ?, toTimestamp(now()), toTimestamp(now()), ?, ?)
-	`, title, description, status, assignedTo, priority).Exec()
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setClauses := make([]string, 0, len(fields)+1)
+	args := make([]interface{}, 0, len(fields)+1)
+	for column, value := range fields {
+		setClauses = append(setClauses, column+" = ?")
+		args = append(args, value)
+	}
+	setClauses = append(setClauses, "updated_at = toTimestamp(now())")
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE jobs SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	return cm.session.Query(query, args...).Consistency(consistency).Exec()
+}
+
+func (cm *CassandraManager) DeleteJob(id gocql.UUID, consistency gocql.Consistency) error {
+	if cm.session == nil {
+		return fmt.Errorf("session not initialized")
+	}
+
+	return cm.session.Query("DELETE FROM jobs WHERE id = ?", id).Consistency(consistency).Exec()
 }
 
 func (cm *CassandraManager) Close() {
@@ -246,6 +496,37 @@ func (cm *CassandraManager) Close() {
 	}
 }
 
+// parseConsistency maps a consistency name from a query parameter or header
+// to a gocql.Consistency. It returns an error if the name is not recognized.
+func parseConsistency(name string) (gocql.Consistency, error) {
+	switch strings.ToLower(name) {
+	case "one":
+		return gocql.One, nil
+	case "quorum":
+		return gocql.Quorum, nil
+	case "local_quorum":
+		return gocql.LocalQuorum, nil
+	case "all":
+		return gocql.All, nil
+	default:
+		return 0, fmt.Errorf("unknown consistency level %q", name)
+	}
+}
+
+// consistencyFromRequest resolves the requested consistency level from the
+// ?consistency= query parameter or the X-Consistency header, falling back to
+// defaultLevel when neither is set.
+func consistencyFromRequest(r *http.Request, defaultLevel gocql.Consistency) (gocql.Consistency, error) {
+	name := r.URL.Query().Get("consistency")
+	if name == "" {
+		name = r.Header.Get("X-Consistency")
+	}
+	if name == "" {
+		return defaultLevel, nil
+	}
+	return parseConsistency(name)
+}
+
 // HTTP handlers
 func (cm *CassandraManager) getRandomJobHandler(w http.ResponseWriter, r *http.Request) {
 	job, err := cm.GetRandomJob()
@@ -278,8 +559,41 @@ func (cm *CassandraManager) getRandomJobHandler(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(response)
 }
 
+// resolvePageSize parses the ?page_size= query parameter, defaulting to
+// defaultPageSize when unset and clamping anything above maxPageSize, so a
+// caller can't force an unbounded Cassandra page fetch.
+func resolvePageSize(raw string) (int, error) {
+	if raw == "" {
+		return defaultPageSize, nil
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid page_size %q", raw)
+	}
+	if parsed > maxPageSize {
+		parsed = maxPageSize
+	}
+	return parsed, nil
+}
+
 func (cm *CassandraManager) getAllJobsHandler(w http.ResponseWriter, r *http.Request) {
-	jobs, err := cm.GetAllJobs()
+	pageSize, err := resolvePageSize(r.URL.Query().Get("page_size"))
+	if err != nil {
+		http.Error(w, "Invalid page_size", http.StatusBadRequest)
+		return
+	}
+
+	var pageState []byte
+	if raw := r.URL.Query().Get("page_state"); raw != "" {
+		decoded, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			http.Error(w, "Invalid page_state", http.StatusBadRequest)
+			return
+		}
+		pageState = decoded
+	}
+
+	jobs, nextPageState, err := cm.GetAllJobs(pageSize, pageState)
 	if err != nil {
 		log.Printf("Error getting all jobs: %v", err)
 		http.Error(w, "Failed to fetch jobs", http.StatusInternalServerError)
@@ -288,56 +602,214 @@ func (cm *CassandraManager) getAllJobsHandler(w http.ResponseWriter, r *http.Req
 
 	hostname, _ := os.Hostname()
 	response := JobsResponse{
-		Jobs:    jobs,
-		Total:   len(jobs),
-		Pod:     hostname,
+		Jobs:     jobs,
+		Total:    len(jobs),
+		Pod:      hostname,
 		Language: "Go",
 	}
+	if len(nextPageState) > 0 {
+		response.NextPageState = base64.StdEncoding.EncodeToString(nextPageState)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 func (cm *CassandraManager) createJobHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Status      string `json:"status"`
-		AssignedTo  string `json:"assigned_to"`
-		Priority    int    `json:"priority"`
+	if cm.clusterMonitor != nil && !cm.clusterMonitor.MeetsMinimum(cm.config.MinCassandraVersion) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, fmt.Sprintf("writes are disabled: cluster is mid-upgrade below MIN_CASSANDRA_VERSION=%s", cm.config.MinCassandraVersion), http.StatusServiceUnavailable)
+		return
 	}
 
+	var req createJobRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
-	if req.Title == "" || req.Description == "" {
-		http.Error(w, "Title and description are required", http.StatusBadRequest)
+	if errs := validateJobRequest(&req); len(errs) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
 		return
 	}
+	applyJobDefaults(&req)
 
-	if req.Status == "" {
-		req.Status = "pending"
+	id, err := cm.CreateJob(req.Title, req.Description, req.Status, req.AssignedTo, req.Priority)
+	if err != nil {
+		log.Printf("Error creating job: %v", err)
+		http.Error(w, "Failed to create job", http.StatusInternalServerError)
+		return
 	}
-	if req.AssignedTo == "" {
-		req.AssignedTo = "unassigned"
+
+	hostname, _ := os.Hostname()
+	response := CreateJobResponse{
+		Message:  "Job created successfully",
+		Pod:      hostname,
+		Language: "Go",
+		ID:       id.String(),
 	}
-	if req.Priority == 0 {
-		req.Priority = 1
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// validateJobHandler runs the same validation createJobHandler does without
+// inserting anything, so the frontend form can show field errors inline
+// before it posts the job.
+func (cm *CassandraManager) validateJobHandler(w http.ResponseWriter, r *http.Request) {
+	var req createJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
 	}
 
-	err := cm.CreateJob(req.Title, req.Description, req.Status, req.AssignedTo, req.Priority)
+	errs := validateJobRequest(&req)
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(errs) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(errs)
+}
+
+func (cm *CassandraManager) getJobByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := gocql.ParseUUID(mux.Vars(r)["id"])
 	if err != nil {
-		log.Printf("Error creating job: %v", err)
-		http.Error(w, "Failed to create job", http.StatusInternalServerError)
+		http.Error(w, "Invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	consistency, err := consistencyFromRequest(r, gocql.Quorum)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := cm.GetJobByID(id, consistency)
+	if err != nil {
+		log.Printf("Error getting job %s: %v", id, err)
+		http.Error(w, "Failed to fetch job from database", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (cm *CassandraManager) updateJobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := gocql.ParseUUID(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	consistency, err := consistencyFromRequest(r, gocql.All)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		Status      *string `json:"status"`
+		AssignedTo  *string `json:"assigned_to"`
+		Priority    *int    `json:"priority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	fields := make(map[string]interface{})
+	if req.Title != nil {
+		fields["title"] = *req.Title
+	}
+	if req.Description != nil {
+		fields["description"] = *req.Description
+	}
+	if req.Status != nil {
+		fields["status"] = *req.Status
+	}
+	if req.AssignedTo != nil {
+		fields["assigned_to"] = *req.AssignedTo
+	}
+	if req.Priority != nil {
+		fields["priority"] = *req.Priority
+	}
+	if len(fields) == 0 {
+		http.Error(w, "No fields to update", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := cm.GetJobByID(id, gocql.Quorum)
+	if err != nil {
+		log.Printf("Error checking job %s before update: %v", id, err)
+		http.Error(w, "Failed to fetch job from database", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	if err := cm.UpdateJob(id, fields, consistency); err != nil {
+		log.Printf("Error updating job %s: %v", id, err)
+		http.Error(w, "Failed to update job", http.StatusInternalServerError)
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	response := SuccessResponse{
+		Message:  "Job updated successfully",
+		Pod:      hostname,
+		Language: "Go",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (cm *CassandraManager) deleteJobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := gocql.ParseUUID(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	consistency, err := consistencyFromRequest(r, gocql.All)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := cm.GetJobByID(id, gocql.Quorum)
+	if err != nil {
+		log.Printf("Error checking job %s before delete: %v", id, err)
+		http.Error(w, "Failed to fetch job from database", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	if err := cm.DeleteJob(id, consistency); err != nil {
+		log.Printf("Error deleting job %s: %v", id, err)
+		http.Error(w, "Failed to delete job", http.StatusInternalServerError)
 		return
 	}
 
 	hostname, _ := os.Hostname()
 	response := SuccessResponse{
-		Message: "Job created successfully",
-		Pod:     hostname,
+		Message:  "Job deleted successfully",
+		Pod:      hostname,
 		Language: "Go",
 	}
 
@@ -349,7 +821,7 @@ func (cm *CassandraManager) healthHandler(w http.ResponseWriter, r *http.Request
 	hostname, _ := os.Hostname()
 	
 	// Test Cassandra connection
-	job, err := cm.GetRandomJob()
+	_, err := cm.GetRandomJob()
 	dbStatus := "connected"
 	if err != nil {
 		dbStatus = "disconnected"
@@ -393,11 +865,43 @@ func (cm *CassandraManager) infoHandler(w http.ResponseWriter, r *http.Request)
 }
 
 func main() {
+	consistency, err := parseConsistency(getEnv("CASSANDRA_CONSISTENCY", "quorum"))
+	if err != nil {
+		log.Fatalf("Invalid CASSANDRA_CONSISTENCY: %v", err)
+	}
+
+	connectTimeout, err := time.ParseDuration(getEnv("CASSANDRA_CONNECT_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid CASSANDRA_CONNECT_TIMEOUT: %v", err)
+	}
+
+	protoVersion := 0
+	if raw := getEnv("CASSANDRA_PROTO_VERSION", ""); raw != "" {
+		protoVersion, err = strconv.Atoi(raw)
+		if err != nil {
+			log.Fatalf("Invalid CASSANDRA_PROTO_VERSION: %v", err)
+		}
+	}
+
 	config := &Config{
-		CassandraHost: getEnv("CASSANDRA_HOST", "cassandra.cassandra.svc.cluster.local"),
-		Keyspace:     getEnv("CASSANDRA_KEYSPACE", "job_tracker"),
-		Datacenter:   getEnv("CASSANDRA_DC", "datacenter1"),
-		Port:         getEnv("PORT", "5000"),
+		CassandraHost:  getEnv("CASSANDRA_HOST", "cassandra.cassandra.svc.cluster.local"),
+		CassandraHosts: splitHosts(getEnv("CASSANDRA_HOSTS", "")),
+		Keyspace:       getEnv("CASSANDRA_KEYSPACE", "job_tracker"),
+		Datacenter:     getEnv("CASSANDRA_DC", "datacenter1"),
+		LocalDC:        getEnv("CASSANDRA_LOCAL_DC", ""),
+		Port:           getEnv("PORT", "5000"),
+		AdminSecret:    getEnv("ADMIN_SECRET", ""),
+		Username:       getEnv("CASSANDRA_USERNAME", ""),
+		Password:       getEnv("CASSANDRA_PASSWORD", ""),
+		TLSCA:          getEnv("CASSANDRA_TLS_CA", ""),
+		TLSCert:        getEnv("CASSANDRA_TLS_CERT", ""),
+		TLSKey:         getEnv("CASSANDRA_TLS_KEY", ""),
+		TLSServerName:  getEnv("CASSANDRA_TLS_SERVER_NAME", ""),
+		ProtoVersion:   protoVersion,
+		Consistency:    consistency,
+		ConnectTimeout: connectTimeout,
+
+		MinCassandraVersion: getEnv("MIN_CASSANDRA_VERSION", ""),
 	}
 
 	// Initialize Cassandra
@@ -406,19 +910,60 @@ func main() {
 		log.Fatalf("Failed to connect to Cassandra: %v", err)
 	}
 
-	if err := cassandraManager.InitializeSchema(); err != nil {
-		log.Fatalf("Failed to initialize Cassandra schema: %v", err)
+	if err := cassandraManager.EnsureKeyspace(); err != nil {
+		log.Fatalf("Failed to ensure Cassandra keyspace: %v", err)
+	}
+
+	migrator, err := NewMigrator(cassandraManager.session)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if err := migrator.Up(0); err != nil {
+		log.Fatalf("Failed to run Cassandra migrations: %v", err)
+	}
+
+	if err := cassandraManager.SeedSampleJobs(); err != nil {
+		log.Fatalf("Failed to seed sample jobs: %v", err)
 	}
 
+	backgroundCtx, cancelBackground := context.WithCancel(context.Background())
+	defer cancelBackground()
+
+	jobServerDone := make(chan struct{})
+
+	clusterMonitor := NewClusterMonitor(cassandraManager, config.MinCassandraVersion)
+	clusterMonitor.Start(backgroundCtx, 30*time.Second)
+	cassandraManager.clusterMonitor = clusterMonitor
+
+	jobServer := scheduler.NewJobServer(cassandraManager.session, 10*time.Second, 30*time.Second)
+	jobServer.RegisterWorker(&createJobWorker{cm: cassandraManager})
+	go func() {
+		jobServer.Run(backgroundCtx)
+		close(jobServerDone)
+	}()
+
 	// Setup router
 	router := mux.NewRouter()
-	
+	router.Use(decompressRequestBody)
+
 	// API routes
 	router.HandleFunc("/", cassandraManager.getRandomJobHandler).Methods("GET")
 	router.HandleFunc("/jobs", cassandraManager.getAllJobsHandler).Methods("GET")
 	router.HandleFunc("/jobs", cassandraManager.createJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/validate", cassandraManager.validateJobHandler).Methods("POST")
+	router.HandleFunc("/jobs/{id}", cassandraManager.getJobByIDHandler).Methods("GET")
+	router.HandleFunc("/jobs/{id}", cassandraManager.updateJobHandler).Methods("PUT")
+	router.HandleFunc("/jobs/{id}", cassandraManager.deleteJobHandler).Methods("DELETE")
 	router.HandleFunc("/health", cassandraManager.healthHandler).Methods("GET")
 	router.HandleFunc("/info", cassandraManager.infoHandler).Methods("GET")
+	router.HandleFunc("/admin/migrate/up", requireAdminSecret(config.AdminSecret, migrator.migrateUpHandler)).Methods("POST")
+	router.HandleFunc("/admin/migrate/down", requireAdminSecret(config.AdminSecret, migrator.migrateDownHandler)).Methods("POST")
+	router.HandleFunc("/admin/migrate/force", requireAdminSecret(config.AdminSecret, migrator.migrateForceHandler)).Methods("POST")
+	router.HandleFunc("/cluster/nodes", clusterMonitor.clusterNodesHandler).Methods("GET")
+	router.HandleFunc("/cluster/version", clusterMonitor.clusterVersionHandler).Methods("GET")
+	router.HandleFunc("/schedules", cassandraManager.createScheduleHandler).Methods("POST")
+	router.HandleFunc("/schedules", cassandraManager.getSchedulesHandler).Methods("GET")
+	router.HandleFunc("/schedules/{id}", cassandraManager.deleteScheduleHandler).Methods("DELETE")
 
 	// Setup CORS
 	c := cors.New(cors.Options{
@@ -460,6 +1005,9 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	cancelBackground()
+	<-jobServerDone
+
 	cassandraManager.Close()
 	log.Println("Server exited")
 }
@@ -469,4 +1017,21 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
+}
+
+// splitHosts parses a comma-separated CASSANDRA_HOSTS value into a host
+// list, trimming whitespace and dropping empty entries.
+func splitHosts(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		host = strings.TrimSpace(host)
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
 }
\ No newline at end of file
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+//go:embed migrations/*.cql
+var migrationFS embed.FS
+
+// migration is a single numbered schema change with its forward (up) and
+// reverse (down) CQL statements, loaded from migrations/<version>_<name>.{up,down}.cql.
+type migration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// Migrator applies versioned CQL migrations embedded at build time, tracking
+// progress in a schema_migrations table. A schema_lock row, written with a
+// lightweight transaction (IF NOT EXISTS), keeps concurrent pods from racing
+// to apply the same migration on boot.
+type Migrator struct {
+	session    *gocql.Session
+	migrations []migration
+}
+
+func NewMigrator(session *gocql.Session) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{session: session, migrations: migrations}, nil
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %v", err)
+	}
+
+	byVersion := map[int64]*migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		parts := strings.SplitN(name, "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		data, err := migrationFS.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version}
+			byVersion[version] = m
+		}
+
+		switch {
+		case strings.HasSuffix(name, ".up.cql"):
+			m.name = strings.TrimSuffix(parts[1], ".up.cql")
+			m.up = string(data)
+		case strings.HasSuffix(name, ".down.cql"):
+			m.down = string(data)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// ensureBookkeepingTables creates the schema_migrations and schema_lock
+// tables used by the migrator, if they don't already exist.
+func (m *Migrator) ensureBookkeepingTables() error {
+	if err := m.session.Query(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			dirty BOOLEAN
+		)
+	`).Exec(); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+
+	return m.session.Query(`
+		CREATE TABLE IF NOT EXISTS schema_lock (
+			name TEXT PRIMARY KEY,
+			owner TEXT
+		)
+	`).Exec()
+}
+
+// lock acquires the schema_lock row via a lightweight transaction so that
+// only one pod applies migrations at a time.
+func (m *Migrator) lock(owner string) error {
+	existing := map[string]interface{}{}
+	applied, err := m.session.Query(`
+		INSERT INTO schema_lock (name, owner) VALUES ('migrator', ?) IF NOT EXISTS
+	`, owner).MapScanCAS(existing)
+	if err != nil {
+		return fmt.Errorf("failed to acquire schema lock: %v", err)
+	}
+	if !applied {
+		return fmt.Errorf("schema lock already held by %v", existing["owner"])
+	}
+	return nil
+}
+
+func (m *Migrator) unlock() error {
+	return m.session.Query(`DELETE FROM schema_lock WHERE name = 'migrator'`).Exec()
+}
+
+// Version returns the highest recorded migration version and whether it is
+// currently marked dirty (a previous migration failed partway through).
+func (m *Migrator) Version() (int64, bool, error) {
+	iter := m.session.Query(`SELECT version, dirty FROM schema_migrations`).Iter()
+
+	var version, maxVersion int64
+	var dirty, dirtyAtMax bool
+	found := false
+	for iter.Scan(&version, &dirty) {
+		if !found || version > maxVersion {
+			maxVersion, dirtyAtMax, found = version, dirty, true
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return 0, false, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	return maxVersion, dirtyAtMax, nil
+}
+
+func (m *Migrator) setVersion(version int64, dirty bool) error {
+	return m.session.Query(`
+		INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)
+	`, version, dirty).Exec()
+}
+
+// splitStatements splits a .cql file body into individual statements on ';'
+// boundaries, skipping blank lines and '--' comments.
+func splitStatements(cql string) []string {
+	var cleaned []string
+	for _, line := range strings.Split(cql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cleaned = append(cleaned, line)
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(strings.Join(cleaned, "\n"), ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
+
+// apply runs cql's statements and records the bookkeeping row for
+// targetVersion as dirty before running and clean afterward.
+func (m *Migrator) apply(mig migration, cql string, targetVersion int64) error {
+	if err := m.setVersion(targetVersion, true); err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(cql) {
+		if err := m.session.Query(stmt).Exec(); err != nil {
+			return fmt.Errorf("migration %d_%s failed: %v", mig.version, mig.name, err)
+		}
+	}
+
+	return m.setVersion(targetVersion, false)
+}
+
+func (m *Migrator) withLock(fn func() error) error {
+	if err := m.ensureBookkeepingTables(); err != nil {
+		return err
+	}
+
+	owner, _ := os.Hostname()
+	if err := m.lock(owner); err != nil {
+		return err
+	}
+	defer m.unlock()
+
+	current, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema is dirty at version %d, call /admin/migrate/force to recover", current)
+	}
+
+	return fn()
+}
+
+// Up applies up to n pending migrations in version order. n <= 0 applies all
+// pending migrations.
+func (m *Migrator) Up(n int) error {
+	return m.withLock(func() error {
+		current, _, err := m.Version()
+		if err != nil {
+			return err
+		}
+
+		applied := 0
+		for _, mig := range m.migrations {
+			if mig.version <= current {
+				continue
+			}
+			if n > 0 && applied >= n {
+				break
+			}
+
+			log.Printf("Applying migration %d_%s.up.cql", mig.version, mig.name)
+			if err := m.apply(mig, mig.up, mig.version); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+}
+
+// Down rolls back up to n applied migrations in reverse version order. n <= 0
+// rolls back every applied migration.
+func (m *Migrator) Down(n int) error {
+	return m.withLock(func() error {
+		current, _, err := m.Version()
+		if err != nil {
+			return err
+		}
+
+		descending := make([]migration, len(m.migrations))
+		copy(descending, m.migrations)
+		sort.Slice(descending, func(i, j int) bool { return descending[i].version > descending[j].version })
+
+		rolledBack := 0
+		for _, mig := range descending {
+			if mig.version > current {
+				continue
+			}
+			if n > 0 && rolledBack >= n {
+				break
+			}
+
+			prevVersion := int64(0)
+			for _, candidate := range m.migrations {
+				if candidate.version < mig.version && candidate.version > prevVersion {
+					prevVersion = candidate.version
+				}
+			}
+
+			log.Printf("Rolling back migration %d_%s.down.cql", mig.version, mig.name)
+			if err := m.apply(mig, mig.down, prevVersion); err != nil {
+				return err
+			}
+			if err := m.session.Query(`DELETE FROM schema_migrations WHERE version = ?`, mig.version).Exec(); err != nil {
+				return fmt.Errorf("failed to clear migration record %d: %v", mig.version, err)
+			}
+			current = prevVersion
+			rolledBack++
+		}
+		return nil
+	})
+}
+
+// Force sets the recorded schema version to v without running any
+// statements, for recovering from a dirty state left by a failed migration.
+func (m *Migrator) Force(v int) error {
+	if err := m.ensureBookkeepingTables(); err != nil {
+		return err
+	}
+
+	// Version() reports the highest recorded row, so rows above v must go.
+	iter := m.session.Query(`SELECT version FROM schema_migrations`).Iter()
+	var recorded int64
+	var stale []int64
+	for iter.Scan(&recorded) {
+		if recorded > int64(v) {
+			stale = append(stale, recorded)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	for _, version := range stale {
+		if err := m.session.Query(`DELETE FROM schema_migrations WHERE version = ?`, version).Exec(); err != nil {
+			return fmt.Errorf("failed to clear migration record %d: %v", version, err)
+		}
+	}
+	return m.setVersion(int64(v), false)
+}
+
+// requireAdminSecret gates an admin handler behind the X-Admin-Secret
+// header so migration recovery endpoints aren't reachable without it.
+func requireAdminSecret(secret string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if secret == "" || r.Header.Get("X-Admin-Secret") != secret {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (m *Migrator) versionResponse(w http.ResponseWriter) {
+	version, dirty, err := m.Version()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"version": version, "dirty": dirty})
+}
+
+func (m *Migrator) migrateUpHandler(w http.ResponseWriter, r *http.Request) {
+	n, err := intQueryParam(r, "n", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := m.Up(n); err != nil {
+		log.Printf("Migration up failed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.versionResponse(w)
+}
+
+func (m *Migrator) migrateDownHandler(w http.ResponseWriter, r *http.Request) {
+	n, err := intQueryParam(r, "n", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := m.Down(n); err != nil {
+		log.Printf("Migration down failed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.versionResponse(w)
+}
+
+func (m *Migrator) migrateForceHandler(w http.ResponseWriter, r *http.Request) {
+	v, err := intQueryParam(r, "version", -1)
+	if err != nil || v < 0 {
+		http.Error(w, "version query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := m.Force(v); err != nil {
+		log.Printf("Migration force failed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.versionResponse(w)
+}
+
+func intQueryParam(r *http.Request, name string, defaultValue int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(raw)
+}
@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		cql  string
+		want []string
+	}{
+		{
+			name: "single statement",
+			cql:  "CREATE TABLE foo (id uuid PRIMARY KEY);",
+			want: []string{"CREATE TABLE foo (id uuid PRIMARY KEY)"},
+		},
+		{
+			name: "multiple statements with comments and blank lines",
+			cql: "-- create foo\n" +
+				"CREATE TABLE foo (id uuid PRIMARY KEY);\n" +
+				"\n" +
+				"-- create bar\n" +
+				"CREATE TABLE bar (id uuid PRIMARY KEY);\n",
+			want: []string{
+				"CREATE TABLE foo (id uuid PRIMARY KEY)",
+				"CREATE TABLE bar (id uuid PRIMARY KEY)",
+			},
+		},
+		{
+			name: "empty input",
+			cql:  "",
+			want: nil,
+		},
+		{
+			name: "only comments",
+			cql:  "-- nothing here\n-- still nothing\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.cql)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitStatements(%q) = %v, want %v", tt.cql, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("statement %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsOrderedByVersion(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations failed: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations returned no migrations")
+	}
+
+	for i, m := range migrations {
+		if m.up == "" {
+			t.Errorf("migration %d (%s) has no up CQL", m.version, m.name)
+		}
+		if m.down == "" {
+			t.Errorf("migration %d (%s) has no down CQL", m.version, m.name)
+		}
+		if i > 0 && migrations[i-1].version >= m.version {
+			t.Errorf("migrations not sorted by version: %d came before %d", migrations[i-1].version, m.version)
+		}
+	}
+}
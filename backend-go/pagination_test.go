@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestResolvePageSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{name: "unset defaults", raw: "", want: defaultPageSize},
+		{name: "within bounds", raw: "10", want: 10},
+		{name: "clamped at max", raw: "100000", want: maxPageSize},
+		{name: "exactly at max", raw: "500", want: maxPageSize},
+		{name: "zero is invalid", raw: "0", wantErr: true},
+		{name: "negative is invalid", raw: "-1", wantErr: true},
+		{name: "non-numeric is invalid", raw: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePageSize(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolvePageSize(%q) = %d, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePageSize(%q) returned unexpected error: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolvePageSize(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed standard 5-field cron expression (minute hour
+// dom month dow), each field holding the set of values that satisfy it.
+type cronSchedule struct {
+	minute [60]bool
+	hour   [24]bool
+	dom    [32]bool // 1-31, index 0 unused
+	month  [13]bool // 1-12, index 0 unused
+	dow    [7]bool  // 0-6, Sunday = 0
+}
+
+var cronFieldRanges = []struct {
+	min, max int
+}{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week
+}
+
+// parseCron parses a standard 5-field cron expression ("minute hour dom
+// month dow"), supporting "*", "*/n" steps, comma lists, and "a-b" ranges
+// (with an optional "/n" step on the range) in each field.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	cs := &cronSchedule{}
+
+	if err := parseCronField(fields[0], cronFieldRanges[0].min, cronFieldRanges[0].max, cs.minute[:]); err != nil {
+		return nil, fmt.Errorf("minute field: %v", err)
+	}
+	if err := parseCronField(fields[1], cronFieldRanges[1].min, cronFieldRanges[1].max, cs.hour[:]); err != nil {
+		return nil, fmt.Errorf("hour field: %v", err)
+	}
+	if err := parseCronField(fields[2], cronFieldRanges[2].min, cronFieldRanges[2].max, cs.dom[:]); err != nil {
+		return nil, fmt.Errorf("day-of-month field: %v", err)
+	}
+	if err := parseCronField(fields[3], cronFieldRanges[3].min, cronFieldRanges[3].max, cs.month[:]); err != nil {
+		return nil, fmt.Errorf("month field: %v", err)
+	}
+	if err := parseCronField(fields[4], cronFieldRanges[4].min, cronFieldRanges[4].max, cs.dow[:]); err != nil {
+		return nil, fmt.Errorf("day-of-week field: %v", err)
+	}
+	return cs, nil
+}
+
+// parseCronField sets into[v] = true for every value v in [min, max] that
+// field selects. field is a comma-separated list of "*", "*/step", "a-b",
+// "a-b/step", or a single integer.
+func parseCronField(field string, min, max int, into []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangeStart, rangeEnd, step := min, max, 1
+
+		valuePart := part
+		if slash := strings.IndexByte(part, '/'); slash != -1 {
+			var err error
+			valuePart = part[:slash]
+			step, err = strconv.Atoi(part[slash+1:])
+			if err != nil || step <= 0 {
+				return fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		switch {
+		case valuePart == "*":
+			// rangeStart/rangeEnd already cover the full field range.
+		case strings.Contains(valuePart, "-"):
+			bounds := strings.SplitN(valuePart, "-", 2)
+			lo, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return fmt.Errorf("invalid range start in %q", part)
+			}
+			hi, err := strconv.Atoi(bounds[1])
+			if err != nil {
+				return fmt.Errorf("invalid range end in %q", part)
+			}
+			rangeStart, rangeEnd = lo, hi
+		default:
+			v, err := strconv.Atoi(valuePart)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			rangeStart, rangeEnd = v, v
+		}
+
+		if rangeStart < min || rangeEnd > max || rangeStart > rangeEnd {
+			return fmt.Errorf("value %q out of range [%d, %d]", part, min, max)
+		}
+		for v := rangeStart; v <= rangeEnd; v += step {
+			into[v] = true
+		}
+	}
+	return nil
+}
+
+// Next returns the next time after `after` (truncated to the minute) that
+// satisfies the expression, searching at most two years ahead. Day-of-month
+// and day-of-week are OR'd together when both are restricted, matching
+// standard cron semantics.
+func (cs *cronSchedule) Next(after time.Time) (time.Time, error) {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	domRestricted := !allTrue(cs.dom[1:])
+	dowRestricted := !allTrue(cs.dow[:])
+
+	limit := after.AddDate(2, 0, 0)
+	for ; t.Before(limit); t = t.Add(time.Minute) {
+		if !cs.month[int(t.Month())] {
+			continue
+		}
+		domMatch := cs.dom[t.Day()]
+		dowMatch := cs.dow[int(t.Weekday())]
+		switch {
+		case domRestricted && dowRestricted:
+			if !domMatch && !dowMatch {
+				continue
+			}
+		case domRestricted:
+			if !domMatch {
+				continue
+			}
+		case dowRestricted:
+			if !dowMatch {
+				continue
+			}
+		}
+		if !cs.hour[t.Hour()] || !cs.minute[t.Minute()] {
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("no matching time found for cron expression within 2 years of %s", after)
+}
+
+func allTrue(vals []bool) bool {
+	for _, v := range vals {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		cron  string
+		after string
+		want  string
+	}{
+		{
+			name:  "every minute",
+			cron:  "* * * * *",
+			after: "2026-07-26T10:00:00Z",
+			want:  "2026-07-26T10:01:00Z",
+		},
+		{
+			name:  "top of every hour",
+			cron:  "0 * * * *",
+			after: "2026-07-26T10:15:00Z",
+			want:  "2026-07-26T11:00:00Z",
+		},
+		{
+			name:  "daily at 02:30",
+			cron:  "30 2 * * *",
+			after: "2026-07-26T10:00:00Z",
+			want:  "2026-07-27T02:30:00Z",
+		},
+		{
+			name:  "every 5 minutes",
+			cron:  "*/5 * * * *",
+			after: "2026-07-26T10:02:00Z",
+			want:  "2026-07-26T10:05:00Z",
+		},
+		{
+			name:  "weekdays at 09:00",
+			cron:  "0 9 * * 1-5",
+			after: "2026-07-24T09:00:00Z", // Friday
+			want:  "2026-07-27T09:00:00Z", // Monday
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := parseCron(tt.cron)
+			if err != nil {
+				t.Fatalf("parseCron(%q) failed: %v", tt.cron, err)
+			}
+			after, err := time.Parse(time.RFC3339, tt.after)
+			if err != nil {
+				t.Fatalf("invalid test fixture time %q: %v", tt.after, err)
+			}
+			got, err := cs.Next(after)
+			if err != nil {
+				t.Fatalf("Next(%s) failed: %v", after, err)
+			}
+			want, err := time.Parse(time.RFC3339, tt.want)
+			if err != nil {
+				t.Fatalf("invalid test fixture time %q: %v", tt.want, err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", after, got, want)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, cron := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+	} {
+		if _, err := parseCron(cron); err == nil {
+			t.Errorf("parseCron(%q) = nil error, want error", cron)
+		}
+	}
+}
+
+func TestNextRunAfterDurationFallback(t *testing.T) {
+	now := time.Date(2026, 7, 26, 10, 0, 0, 0, time.UTC)
+	got, err := nextRunAfter("5m", now)
+	if err != nil {
+		t.Fatalf("nextRunAfter(\"5m\") failed: %v", err)
+	}
+	want := now.Add(5 * time.Minute)
+	if !got.Equal(want) {
+		t.Errorf("nextRunAfter(\"5m\") = %s, want %s", got, want)
+	}
+}
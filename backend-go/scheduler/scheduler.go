@@ -0,0 +1,200 @@
+// Package scheduler implements a periodic-scheduler pattern for recurring
+// jobs: each schedule's Cron is a standard 5-field cron expression (or, for
+// backward compatibility, a plain Go duration like "5m"), and a JobServer
+// polls Cassandra for schedules that are due, claims one atomically via a
+// lightweight-transaction CAS on job_status, and dispatches it to a
+// registered Worker.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// ScheduledJob is a single due invocation of a schedule, carrying whatever
+// payload the schedule was created with.
+type ScheduledJob struct {
+	ScheduleID gocql.UUID
+	Cron       string
+	Payload    string
+	NextRun    time.Time
+}
+
+// Worker executes a ScheduledJob that the JobServer has claimed.
+type Worker interface {
+	Name() string
+	Run(job *ScheduledJob) error
+}
+
+// JobServer polls the schedules table for due work, leases it via CAS on
+// job_status so only one pod runs a given schedule at a time, dispatches
+// claimed jobs to a registered Worker by name (workers are looked up by the
+// payload's "worker" field), and reschedules each job from its cron
+// expression for the next due run.
+type JobServer struct {
+	session   *gocql.Session
+	pollEvery time.Duration
+	leaseFor  time.Duration
+
+	mu      sync.Mutex
+	workers map[string]Worker
+
+	wg sync.WaitGroup
+}
+
+func NewJobServer(session *gocql.Session, pollEvery, leaseFor time.Duration) *JobServer {
+	return &JobServer{
+		session:   session,
+		pollEvery: pollEvery,
+		leaseFor:  leaseFor,
+		workers:   make(map[string]Worker),
+	}
+}
+
+// RegisterWorker makes w available to handle claimed jobs dispatched under
+// w.Name().
+func (s *JobServer) RegisterWorker(w Worker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers[w.Name()] = w
+}
+
+// Run polls on s.pollEvery until ctx is cancelled, then waits for in-flight
+// workers to finish before returning so graceful shutdown doesn't interrupt
+// a job mid-run.
+func (s *JobServer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+			s.pollOnce()
+		}
+	}
+}
+
+func (s *JobServer) pollOnce() {
+	due, err := s.dueSchedules()
+	if err != nil {
+		log.Printf("scheduler: failed to list due schedules: %v", err)
+		return
+	}
+
+	for _, job := range due {
+		if !s.claim(job.ScheduleID) {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(job ScheduledJob) {
+			defer s.wg.Done()
+			s.dispatch(job)
+		}(job)
+	}
+}
+
+func (s *JobServer) dueSchedules() ([]ScheduledJob, error) {
+	iter := s.session.Query(`SELECT id, cron, payload, next_run FROM schedules`).Iter()
+
+	var due []ScheduledJob
+	var id gocql.UUID
+	var cron, payload string
+	var nextRun time.Time
+	now := time.Now()
+	for iter.Scan(&id, &cron, &payload, &nextRun) {
+		if !nextRun.After(now) {
+			due = append(due, ScheduledJob{ScheduleID: id, Cron: cron, Payload: payload, NextRun: nextRun})
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %v", err)
+	}
+	return due, nil
+}
+
+// claim leases schedule id for s.leaseFor via a conditional UPDATE, so a
+// second pod racing to poll the same schedule loses the CAS and skips it.
+func (s *JobServer) claim(id gocql.UUID) bool {
+	owner, _ := os.Hostname()
+	now := time.Now()
+	leaseUntil := now.Add(s.leaseFor)
+
+	existing := map[string]interface{}{}
+	applied, err := s.session.Query(`
+		UPDATE job_status SET leased_by = ?, lease_until = ?
+		WHERE schedule_id = ? IF lease_until < ?
+	`, owner, leaseUntil, id, now).MapScanCAS(existing)
+	if err != nil {
+		log.Printf("scheduler: failed to claim schedule %s: %v", id, err)
+		return false
+	}
+	return applied
+}
+
+func (s *JobServer) dispatch(job ScheduledJob) {
+	var envelope struct {
+		Worker string `json:"worker"`
+	}
+	if err := json.Unmarshal([]byte(job.Payload), &envelope); err != nil {
+		log.Printf("scheduler: schedule %s has unparseable payload: %v", job.ScheduleID, err)
+		return
+	}
+
+	s.mu.Lock()
+	worker, ok := s.workers[envelope.Worker]
+	s.mu.Unlock()
+
+	if !ok {
+		log.Printf("scheduler: no worker registered for schedule %s (worker=%q)", job.ScheduleID, envelope.Worker)
+		return
+	}
+
+	if err := worker.Run(&job); err != nil {
+		log.Printf("scheduler: worker %s failed for schedule %s: %v", worker.Name(), job.ScheduleID, err)
+		return
+	}
+
+	now := time.Now()
+	if err := s.session.Query(`
+		UPDATE job_status SET last_run = ? WHERE schedule_id = ?
+	`, now, job.ScheduleID).Exec(); err != nil {
+		log.Printf("scheduler: failed to record last_run for schedule %s: %v", job.ScheduleID, err)
+	}
+
+	nextRun, err := nextRunAfter(job.Cron, now)
+	if err != nil {
+		log.Printf("scheduler: schedule %s has unparseable cron %q, not rescheduling: %v", job.ScheduleID, job.Cron, err)
+		return
+	}
+	if err := s.session.Query(`
+		UPDATE schedules SET next_run = ? WHERE id = ?
+	`, nextRun, job.ScheduleID).Exec(); err != nil {
+		log.Printf("scheduler: failed to reschedule %s: %v", job.ScheduleID, err)
+	}
+}
+
+// nextRunAfter parses cron as a standard 5-field cron expression and
+// returns its next occurrence after now. For backward compatibility with
+// schedules created before cron expressions were supported, a plain Go
+// duration (e.g. "5m") is also accepted and treated as a fixed interval
+// from now.
+func nextRunAfter(cron string, now time.Time) (time.Time, error) {
+	if cs, err := parseCron(cron); err == nil {
+		return cs.Next(now)
+	}
+	if interval, err := time.ParseDuration(cron); err == nil {
+		return now.Add(interval), nil
+	}
+	return time.Time{}, fmt.Errorf("not a valid cron expression or duration")
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeWorker struct {
+	name string
+	err  error
+	ran  bool
+}
+
+func (w *fakeWorker) Name() string { return w.name }
+func (w *fakeWorker) Run(job *ScheduledJob) error {
+	w.ran = true
+	return w.err
+}
+
+// newTestJobServer builds a JobServer with no Cassandra session, for
+// exercising dispatch paths that return before touching the database.
+func newTestJobServer() *JobServer {
+	return NewJobServer(nil, 0, 0)
+}
+
+func TestDispatchUnparseablePayload(t *testing.T) {
+	s := newTestJobServer()
+	worker := &fakeWorker{name: "create-job"}
+	s.RegisterWorker(worker)
+
+	s.dispatch(ScheduledJob{Payload: "not json"})
+
+	if worker.ran {
+		t.Error("dispatch should not have run a worker for an unparseable payload")
+	}
+}
+
+func TestDispatchUnknownWorker(t *testing.T) {
+	s := newTestJobServer()
+	worker := &fakeWorker{name: "create-job"}
+	s.RegisterWorker(worker)
+
+	s.dispatch(ScheduledJob{Payload: `{"worker":"does-not-exist"}`})
+
+	if worker.ran {
+		t.Error("dispatch should not have run a worker that wasn't addressed by the payload")
+	}
+}
+
+func TestDispatchWorkerFailureSkipsReschedule(t *testing.T) {
+	s := newTestJobServer()
+	worker := &fakeWorker{name: "create-job", err: errors.New("boom")}
+	s.RegisterWorker(worker)
+
+	// A nil session would panic if dispatch tried to record last_run or
+	// reschedule after a failed run; reaching here without panicking
+	// confirms it returns as soon as worker.Run fails.
+	s.dispatch(ScheduledJob{Payload: `{"worker":"create-job"}`})
+
+	if !worker.ran {
+		t.Error("expected the registered worker to have been invoked")
+	}
+}
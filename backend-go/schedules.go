@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+
+	"backend-go/scheduler"
+)
+
+// Schedule is a user-defined recurring job: Cron is either a cron
+// expression or (for now) a plain Go duration like "5m", and Payload is the
+// JSON envelope a registered scheduler.Worker understands.
+type Schedule struct {
+	ID        string    `json:"id"`
+	Cron      string    `json:"cron"`
+	Payload   string    `json:"payload"`
+	NextRun   time.Time `json:"next_run"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (cm *CassandraManager) CreateSchedule(cron, payload string) (gocql.UUID, error) {
+	if cm.session == nil {
+		return gocql.UUID{}, fmt.Errorf("session not initialized")
+	}
+
+	id, err := gocql.RandomUUID()
+	if err != nil {
+		return gocql.UUID{}, fmt.Errorf("failed to generate schedule id: %v", err)
+	}
+
+	now := time.Now()
+	if err := cm.session.Query(`
+		INSERT INTO schedules (id, cron, payload, next_run, created_at) VALUES (?, ?, ?, ?, ?)
+	`, id, cron, payload, now, now).Exec(); err != nil {
+		return gocql.UUID{}, fmt.Errorf("failed to create schedule: %v", err)
+	}
+
+	if err := cm.session.Query(`
+		INSERT INTO job_status (schedule_id, lease_until) VALUES (?, ?)
+	`, id, time.Unix(0, 0)).Exec(); err != nil {
+		return gocql.UUID{}, fmt.Errorf("failed to initialize schedule lease: %v", err)
+	}
+
+	return id, nil
+}
+
+func (cm *CassandraManager) GetSchedules() ([]Schedule, error) {
+	if cm.session == nil {
+		return nil, fmt.Errorf("session not initialized")
+	}
+
+	iter := cm.session.Query(`SELECT id, cron, payload, next_run, created_at FROM schedules`).Iter()
+
+	var schedules []Schedule
+	var s Schedule
+	for iter.Scan(&s.ID, &s.Cron, &s.Payload, &s.NextRun, &s.CreatedAt) {
+		schedules = append(schedules, s)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %v", err)
+	}
+	return schedules, nil
+}
+
+func (cm *CassandraManager) DeleteSchedule(id gocql.UUID) error {
+	if cm.session == nil {
+		return fmt.Errorf("session not initialized")
+	}
+
+	if err := cm.session.Query(`DELETE FROM schedules WHERE id = ?`, id).Exec(); err != nil {
+		return fmt.Errorf("failed to delete schedule: %v", err)
+	}
+	return cm.session.Query(`DELETE FROM job_status WHERE schedule_id = ?`, id).Exec()
+}
+
+func (cm *CassandraManager) createScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Cron    string `json:"cron"`
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Cron == "" || req.Payload == "" {
+		http.Error(w, "cron and payload are required", http.StatusBadRequest)
+		return
+	}
+
+	id, err := cm.CreateSchedule(req.Cron, req.Payload)
+	if err != nil {
+		log.Printf("Error creating schedule: %v", err)
+		http.Error(w, "Failed to create schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": id.String()})
+}
+
+func (cm *CassandraManager) getSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	schedules, err := cm.GetSchedules()
+	if err != nil {
+		log.Printf("Error getting schedules: %v", err)
+		http.Error(w, "Failed to fetch schedules", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"schedules": schedules})
+}
+
+func (cm *CassandraManager) deleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := gocql.ParseUUID(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid schedule id", http.StatusBadRequest)
+		return
+	}
+
+	if err := cm.DeleteSchedule(id); err != nil {
+		log.Printf("Error deleting schedule %s: %v", id, err)
+		http.Error(w, "Failed to delete schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createJobWorker is a scheduler.Worker that creates a new Job each time its
+// schedule comes due, stamping the job with the schedule it came from.
+type createJobWorker struct {
+	cm *CassandraManager
+}
+
+func (w *createJobWorker) Name() string { return "create-job" }
+
+func (w *createJobWorker) Run(job *scheduler.ScheduledJob) error {
+	var payload struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Priority    int    `json:"priority"`
+	}
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %v", err)
+	}
+	if payload.Priority == 0 {
+		payload.Priority = 1
+	}
+
+	id, err := w.cm.CreateJob(payload.Title, payload.Description, "pending", "unassigned", payload.Priority)
+	if err != nil {
+		return err
+	}
+	return w.cm.session.Query(`UPDATE jobs SET schedule_id = ? WHERE id = ?`, job.ScheduleID.String(), id).Exec()
+}
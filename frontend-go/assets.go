@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+
+	"github.com/justinas/nosurf"
+)
+
+// web/dist holds the compiled SPA: a static index.html plus fingerprinted
+// CSS/JS built by web/fingerprint.sh. It replaces the old inline indexHTML
+// template literal so the frontend can be iterated on as real files.
+//
+//go:embed web/dist
+var distFS embed.FS
+
+// webAssets roots distFS at web/dist so paths match what's served
+// ("index.html", "app.<hash>.css", ...) without the embed prefix.
+var webAssets = func() fs.FS {
+	sub, err := fs.Sub(distFS, "web/dist")
+	if err != nil {
+		log.Fatalf("failed to load embedded web assets: %v", err)
+	}
+	return sub
+}()
+
+// indexHandler serves the SPA shell. It's always no-cache: index.html is
+// the one file whose content changes on every deploy (it references the
+// current build's asset fingerprints), so caching it would pin clients to
+// stale hashes.
+func (bs *BackendService) indexHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := fs.ReadFile(webAssets, "index.html")
+	if err != nil {
+		log.Printf("Error reading embedded index.html: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// The page needs its CSRF token to send back on mutating fetch() calls;
+	// nosurf.Token reads it from the cookie its middleware already set on
+	// this request.
+	meta := []byte(fmt.Sprintf(`<meta name="csrf-token" content="%s">`, nosurf.Token(r)))
+	data = bytes.Replace(data, []byte("</head>"), append(meta, []byte("</head>")...), 1)
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(data)
+}
+
+// assetsHandler serves fingerprinted static assets under /assets/ with a
+// long-lived immutable cache: a new build ships its CSS/JS under a new
+// fingerprint rather than overwriting the old one, so it's always safe to
+// cache an existing fingerprint forever.
+func assetsHandler() http.Handler {
+	fileServer := http.FileServer(http.FS(webAssets))
+	return http.StripPrefix("/assets/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		fileServer.ServeHTTP(w, r)
+	}))
+}
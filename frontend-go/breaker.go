@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// breakerMinSamples is how many calls must land in the rolling window
+// before a failure ratio is trusted enough to trip the breaker; otherwise a
+// single cold-start failure would open the circuit.
+const breakerMinSamples = 5
+
+// breakerState is one of the three states a CircuitBreaker can be in.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitOpenError is returned when the breaker is open (or half-open with
+// a trial already in flight), so HTTP handlers can translate it to a 503
+// with Retry-After instead of an opaque 500.
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open, retry after %s", e.RetryAfter)
+}
+
+type breakerOutcome struct {
+	at      time.Time
+	success bool
+}
+
+// CircuitBreaker trips to open once a rolling window of calls sees a
+// failure ratio at or above threshold. While open it short-circuits calls
+// until window has elapsed, then allows a single half-open trial call to
+// decide whether to close again or re-open.
+type CircuitBreaker struct {
+	threshold float64
+	window    time.Duration
+
+	mu            sync.Mutex
+	state         breakerState
+	history       []breakerOutcome
+	openSince     time.Time
+	trialInFlight bool
+}
+
+func NewCircuitBreaker(threshold float64, window time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		window:    window,
+		state:     breakerClosed,
+	}
+}
+
+// State reports the breaker's current state for health reporting.
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state.String()
+}
+
+// allow reports whether a call may proceed, and if not, how long the caller
+// should wait before retrying.
+func (cb *CircuitBreaker) allow() (bool, time.Duration) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		elapsed := time.Since(cb.openSince)
+		if elapsed >= cb.window {
+			cb.state = breakerHalfOpen
+			cb.trialInFlight = true
+			return true, 0
+		}
+		return false, cb.window - elapsed
+	case breakerHalfOpen:
+		if cb.trialInFlight {
+			return false, cb.window
+		}
+		cb.trialInFlight = true
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// record stores the outcome of an allowed call and updates the breaker's
+// state accordingly.
+func (cb *CircuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+
+	if cb.state == breakerHalfOpen {
+		cb.trialInFlight = false
+		if success {
+			cb.state = breakerClosed
+			cb.history = nil
+		} else {
+			cb.state = breakerOpen
+			cb.openSince = now
+		}
+		return
+	}
+
+	cb.history = append(cb.history, breakerOutcome{at: now, success: success})
+	cutoff := now.Add(-cb.window)
+	kept := cb.history[:0]
+	for _, o := range cb.history {
+		if o.at.After(cutoff) {
+			kept = append(kept, o)
+		}
+	}
+	cb.history = kept
+
+	if len(cb.history) < breakerMinSamples {
+		return
+	}
+
+	failures := 0
+	for _, o := range cb.history {
+		if !o.success {
+			failures++
+		}
+	}
+	if float64(failures)/float64(len(cb.history)) >= cb.threshold {
+		cb.state = breakerOpen
+		cb.openSince = now
+	}
+}
+
+// callThrough runs fn through the breaker, retrying failures up to
+// maxRetries times with exponential backoff and jitter between attempts. It
+// short-circuits immediately with a CircuitOpenError if the breaker is open.
+func (cb *CircuitBreaker) callThrough(maxRetries int, baseDelay time.Duration, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		allowed, retryAfter := cb.allow()
+		if !allowed {
+			return &CircuitOpenError{RetryAfter: retryAfter}
+		}
+
+		lastErr = fn()
+		cb.record(lastErr == nil)
+		if lastErr == nil {
+			return nil
+		}
+
+		if attempt < maxRetries {
+			backoff := time.Duration(float64(baseDelay) * math.Pow(2, float64(attempt)))
+			jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+			time.Sleep(backoff/2 + jitter)
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/ulikunitz/xz"
+)
+
+// CompressionCodec can both decode a response body the upstream compressed
+// and compress an outgoing request body, so the same registry serves
+// content-encoding negotiation in both directions.
+type CompressionCodec interface {
+	Name() string
+	NewReader(r io.Reader) (io.ReadCloser, error)
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string                                  { return "gzip" }
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error)  { return gzip.NewReader(r) }
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+
+type flateCodec struct{}
+
+func (flateCodec) Name() string { return "deflate" }
+func (flateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+func (flateCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.DefaultCompression)
+}
+
+type bzip2Codec struct{}
+
+func (bzip2Codec) Name() string { return "bzip2" }
+func (bzip2Codec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := bzip2.NewReader(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(reader), nil
+}
+func (bzip2Codec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return bzip2.NewWriter(w, nil)
+}
+
+type xzCodec struct{}
+
+func (xzCodec) Name() string { return "xz" }
+func (xzCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := xz.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(reader), nil
+}
+func (xzCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return xz.NewWriter(w)
+}
+
+// compressionCodecs is the registry the frontend proxy handlers and
+// BackendService both plug into for transparent content-encoding support.
+var compressionCodecs = map[string]CompressionCodec{
+	"gzip":    gzipCodec{},
+	"deflate": flateCodec{},
+	"bzip2":   bzip2Codec{},
+	"xz":      xzCodec{},
+}
+
+// acceptEncodingHeader is what every outgoing upstream request advertises.
+const acceptEncodingHeader = "gzip, deflate, bzip2, xz"
+
+// compressedRequestThreshold is the payload size above which postJSON
+// compresses the body before sending it upstream; small bodies aren't
+// worth the CPU.
+const compressedRequestThreshold = 8 * 1024
+
+// decodingTransport advertises Accept-Encoding on every request and
+// transparently decodes whichever encoding the upstream responds with, so
+// callers never see a compressed body.
+type decodingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *decodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncodingHeader)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(resp.Header.Get("Content-Encoding"))
+	codec, ok := compressionCodecs[encoding]
+	if !ok {
+		return resp, nil
+	}
+
+	decoded, err := codec.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %v", encoding, err)
+	}
+	resp.Body = &decodedBody{ReadCloser: decoded, orig: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decodedBody wraps a codec's decoded reader so closing it also closes the
+// original compressed response body. Codec readers (gzip, flate, and the
+// io.NopCloser-wrapped bzip2/xz readers) only release their own internal
+// state on Close, never the wrapped reader, which otherwise leaves the
+// underlying connection unreturned to the transport's pool.
+type decodedBody struct {
+	io.ReadCloser
+	orig io.ReadCloser
+}
+
+func (b *decodedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if origErr := b.orig.Close(); err == nil {
+		err = origErr
+	}
+	return err
+}
+
+// postJSON POSTs data to path on the backend, compressing the body with
+// gzip and setting Content-Encoding when it's large enough to be worth it.
+func (bs *BackendService) postJSON(path string, data []byte) (*http.Response, error) {
+	body := data
+	encoding := ""
+
+	if len(data) >= compressedRequestThreshold {
+		compressed, err := compressBody("gzip", data)
+		if err == nil {
+			body = compressed
+			encoding = "gzip"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(bs.ctx, http.MethodPost, bs.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+
+	return bs.Client.Do(req)
+}
+
+// compressBody compresses data with the named codec, returning the
+// compressed bytes.
+func compressBody(codecName string, data []byte) ([]byte, error) {
+	codec, ok := compressionCodecs[codecName]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression codec %q", codecName)
+	}
+
+	var buf bytes.Buffer
+	writer, err := codec.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeReadCloser records whether Close was called so tests can assert it
+// propagated.
+type fakeReadCloser struct {
+	io.Reader
+	closed bool
+}
+
+func (f *fakeReadCloser) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestDecodedBodyCloseClosesOriginal(t *testing.T) {
+	orig := &fakeReadCloser{Reader: strings.NewReader("")}
+	decoded := &fakeReadCloser{Reader: strings.NewReader("")}
+	body := &decodedBody{ReadCloser: decoded, orig: orig}
+
+	if err := body.Close(); err != nil {
+		t.Fatalf("Close returned unexpected error: %v", err)
+	}
+	if !decoded.closed {
+		t.Error("decodedBody.Close did not close the decoded reader")
+	}
+	if !orig.closed {
+		t.Error("decodedBody.Close did not close the original response body")
+	}
+}
+
+// jobBatchPayload builds a ~1MB JSON payload representative of a bulk job
+// import, for benchmarking compressBody's win on realistic data.
+func jobBatchPayload(b *testing.B) []byte {
+	b.Helper()
+
+	type bulkJob struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Status      string `json:"status"`
+		AssignedTo  string `json:"assigned_to"`
+		Priority    int    `json:"priority"`
+	}
+
+	job := bulkJob{
+		Title:       "Provision edge node rack-42-unit-7",
+		Description: "Recurring maintenance job generated from the nightly fleet inventory scan; includes firmware and certificate checks.",
+		Status:      "pending",
+		AssignedTo:  "unassigned",
+		Priority:    2,
+	}
+	one, err := json.Marshal(job)
+	if err != nil {
+		b.Fatalf("failed to marshal benchmark job: %v", err)
+	}
+
+	// Each job adds its encoded size plus a separating comma.
+	var jobs []bulkJob
+	for len(jobs)*(len(one)+1) < 1024*1024 {
+		jobs = append(jobs, job)
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		b.Fatalf("failed to marshal benchmark payload: %v", err)
+	}
+	return data
+}
+
+func BenchmarkCompress1MBJobBatch(b *testing.B) {
+	data := jobBatchPayload(b)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		compressed, err := compressBody("gzip", data)
+		if err != nil {
+			b.Fatalf("compressBody failed: %v", err)
+		}
+		if i == 0 {
+			b.ReportMetric(float64(len(compressed))/float64(len(data)), "compression-ratio")
+		}
+	}
+}
@@ -3,29 +3,60 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html/template"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/coreos/go-systemd/v22/daemon"
 	"github.com/gorilla/mux"
+	"github.com/justinas/nosurf"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"frontend-go/metrics"
 )
 
 // Configuration
 type Config struct {
 	BackendURL string
 	Port       string
+
+	BreakerThreshold float64
+	BreakerWindow    time.Duration
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+
+	ServiceName  string
+	OTelEndpoint string
+
+	// CORSAllowedOrigins is a comma-separated allow-list from
+	// CORS_ALLOWED_ORIGINS. Empty means same-origin only: the CORS setup in
+	// main rejects every cross-origin caller rather than passing this
+	// through as rs/cors' "allow all" empty-list default.
+	CORSAllowedOrigins []string
 }
 
 // Backend service
 type BackendService struct {
 	BaseURL string
 	Client  *http.Client
+
+	// ctx is cancelled by main on SIGTERM so in-flight upstream calls fail
+	// fast instead of blocking graceful shutdown until they time out.
+	ctx context.Context
+
+	breaker        *CircuitBreaker
+	maxRetries     int
+	retryBaseDelay time.Duration
 }
 
 // Response models
@@ -39,23 +70,24 @@ type JobResponse struct {
 
 type ClusterInfo struct {
 	ConnectionStatus string `json:"connection_status"`
-	Language        string `json:"language"`
+	Language         string `json:"language"`
 }
 
 type JobsResponse struct {
-	Jobs    []Job `json:"jobs"`
-	Total   int   `json:"total"`
-	Pod     string `json:"pod"`
-	Language string `json:"language"`
+	Jobs          []Job  `json:"jobs"`
+	Total         int    `json:"total"`
+	Pod           string `json:"pod"`
+	Language      string `json:"language"`
+	NextPageState string `json:"next_page_state,omitempty"`
 }
 
 type HealthResponse struct {
-	Status   string `json:"status"`
-	Pod      string `json:"pod"`
+	Status    string `json:"status"`
+	Pod       string `json:"pod"`
 	Timestamp string `json:"timestamp"`
-	Database string `json:"database"`
-	Language string `json:"language"`
-	Version  string `json:"version"`
+	Database  string `json:"database"`
+	Language  string `json:"language"`
+	Version   string `json:"version"`
 }
 
 type InfoResponse struct {
@@ -76,65 +108,196 @@ type Job struct {
 	Priority    int       `json:"priority"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	ScheduleID string     `json:"schedule_id,omitempty"`
+	RunAt      *time.Time `json:"run_at,omitempty"`
+	LeaseUntil *time.Time `json:"lease_until,omitempty"`
 }
 
-func NewBackendService(baseURL string) *BackendService {
+// Schedule is a user-defined recurring job, proxied through to the backend.
+type Schedule struct {
+	ID        string    `json:"id"`
+	Cron      string    `json:"cron"`
+	Payload   string    `json:"payload"`
+	NextRun   time.Time `json:"next_run"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SchedulesResponse struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+func NewBackendService(ctx context.Context, baseURL string, breakerThreshold float64, breakerWindow time.Duration, maxRetries int, retryBaseDelay time.Duration) *BackendService {
 	return &BackendService{
 		BaseURL: baseURL,
+		ctx:     ctx,
 		Client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(&decodingTransport{base: http.DefaultTransport}),
 		},
+		breaker:        NewCircuitBreaker(breakerThreshold, breakerWindow),
+		maxRetries:     maxRetries,
+		retryBaseDelay: retryBaseDelay,
+	}
+}
+
+// get issues a context-aware GET against path so the call is cancelled the
+// moment bs.ctx is (e.g. on SIGTERM), instead of blocking graceful shutdown
+// until the client's own timeout elapses.
+func (bs *BackendService) get(path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(bs.ctx, http.MethodGet, bs.BaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %v", err)
 	}
+	return bs.Client.Do(req)
 }
 
 func (bs *BackendService) GetRandomJob() (*JobResponse, error) {
-	resp, err := bs.Client.Get(bs.BaseURL + "/")
+	start := time.Now()
+	var jobResp JobResponse
+	err := bs.breaker.callThrough(bs.maxRetries, bs.retryBaseDelay, func() error {
+		resp, err := bs.get("/")
+		if err != nil {
+			return fmt.Errorf("failed to fetch random job: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("backend returned status %d", resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&jobResp); err != nil {
+			return fmt.Errorf("failed to decode response: %v", err)
+		}
+		return nil
+	})
+	metrics.ObserveBackendCall("GetRandomJob", time.Since(start), err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch random job: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &jobResp, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
+// GetAllJobs fetches a single page of jobs. pageState is the opaque cursor
+// returned as NextPageState by the previous call, or "" for the first page;
+// callers that need every job (e.g. the SSE poller) must follow
+// NextPageState until it's empty rather than treating one page as the
+// whole list.
+func (bs *BackendService) GetAllJobs(pageState string) (*JobsResponse, error) {
+	start := time.Now()
+	var jobsResp JobsResponse
+	err := bs.breaker.callThrough(bs.maxRetries, bs.retryBaseDelay, func() error {
+		path := "/jobs"
+		if pageState != "" {
+			path += "?page_state=" + url.QueryEscape(pageState)
+		}
+		resp, err := bs.get(path)
+		if err != nil {
+			return fmt.Errorf("failed to fetch all jobs: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("backend returned status %d", resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&jobsResp); err != nil {
+			return fmt.Errorf("failed to decode response: %v", err)
+		}
+		return nil
+	})
+	metrics.ObserveBackendCall("GetAllJobs", time.Since(start), err)
+	if err != nil {
+		return nil, err
 	}
+	return &jobsResp, nil
+}
 
-	var jobResp JobResponse
-	if err := json.NewDecoder(resp.Body).Decode(&jobResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+// CreateJob posts jobData to the backend. If the backend rejects it with a
+// 400 field-error map, that's reported back via fieldErrors rather than err
+// (it's a rejected input, not a failed call) and doesn't count against the
+// circuit breaker.
+func (bs *BackendService) CreateJob(jobData map[string]interface{}) (result *map[string]interface{}, fieldErrors map[string]string, err error) {
+	jsonData, err := json.Marshal(jobData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal job data: %v", err)
 	}
 
-	return &jobResp, nil
+	start := time.Now()
+	var decoded map[string]interface{}
+	err = bs.breaker.callThrough(bs.maxRetries, bs.retryBaseDelay, func() error {
+		resp, err := bs.postJSON("/jobs", jsonData)
+		if err != nil {
+			return fmt.Errorf("failed to create job: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusBadRequest {
+			if err := json.NewDecoder(resp.Body).Decode(&fieldErrors); err != nil {
+				return fmt.Errorf("failed to decode validation errors: %v", err)
+			}
+			return nil
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("backend returned status %d", resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			return fmt.Errorf("failed to decode response: %v", err)
+		}
+		return nil
+	})
+	metrics.ObserveBackendCall("CreateJob", time.Since(start), err)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors, nil
+	}
+	return &decoded, nil, nil
 }
 
-func (bs *BackendService) GetAllJobs() (*JobsResponse, error) {
-	resp, err := bs.Client.Get(bs.BaseURL + "/jobs")
+// ValidateJob asks the backend to validate jobData without creating
+// anything, for the form's inline field errors. Like CreateSchedule, this
+// doesn't go through the circuit breaker: it's a cheap, side-effect-free
+// check, not a call worth tripping the breaker over.
+func (bs *BackendService) ValidateJob(jobData map[string]interface{}) (map[string]string, error) {
+	jsonData, err := json.Marshal(jobData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch all jobs: %v", err)
+		return nil, fmt.Errorf("failed to marshal job data: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := bs.postJSON("/jobs/validate", jsonData)
+	metrics.ObserveBackendCall("ValidateJob", time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate job: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
 		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
 	}
 
-	var jobsResp JobsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&jobsResp); err != nil {
+	var fieldErrors map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&fieldErrors); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
-
-	return &jobsResp, nil
+	return fieldErrors, nil
 }
 
-func (bs *BackendService) CreateJob(jobData map[string]interface{}) (*map[string]interface{}, error) {
-	jsonData, err := json.Marshal(jobData)
+func (bs *BackendService) CreateSchedule(cron, payload string) (*map[string]interface{}, error) {
+	jsonData, err := json.Marshal(map[string]string{"cron": cron, "payload": payload})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal job data: %v", err)
+		return nil, fmt.Errorf("failed to marshal schedule data: %v", err)
 	}
 
-	resp, err := bs.Client.Post(bs.BaseURL+"/jobs", "application/json", 
-		&jsonDataBuffer{jsonData})
+	start := time.Now()
+	resp, err := bs.postJSON("/schedules", jsonData)
+	metrics.ObserveBackendCall("CreateSchedule", time.Since(start), err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create job: %v", err)
+		return nil, fmt.Errorf("failed to create schedule: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -146,14 +309,15 @@ func (bs *BackendService) CreateJob(jobData map[string]interface{}) (*map[string
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
-
 	return &result, nil
 }
 
-func (bs *BackendService) GetHealth() (*HealthResponse, error) {
-	resp, err := bs.Client.Get(bs.BaseURL + "/health")
+func (bs *BackendService) GetSchedules() (*SchedulesResponse, error) {
+	start := time.Now()
+	resp, err := bs.get("/schedules")
+	metrics.ObserveBackendCall("GetSchedules", time.Since(start), err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch health status: %v", err)
+		return nil, fmt.Errorf("failed to fetch schedules: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -161,35 +325,105 @@ func (bs *BackendService) GetHealth() (*HealthResponse, error) {
 		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
 	}
 
-	var healthResp HealthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&healthResp); err != nil {
+	var schedulesResp SchedulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&schedulesResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
+	return &schedulesResp, nil
+}
 
-	return &healthResp, nil
+func (bs *BackendService) DeleteSchedule(id string) error {
+	req, err := http.NewRequestWithContext(bs.ctx, http.MethodDelete, bs.BaseURL+"/schedules/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build delete request: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := bs.Client.Do(req)
+	metrics.ObserveBackendCall("DeleteSchedule", time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
+	return nil
 }
 
-// Helper type for http.Client.Post
-type jsonDataBuffer struct {
-	data []byte
+// GetJobByID fetches a single job by ID for the detail page linked from a
+// just-created job's response. Like GetSchedules, it skips the breaker:
+// a missing/unknown ID is a client concern, not backend instability.
+func (bs *BackendService) GetJobByID(id string) (*Job, error) {
+	start := time.Now()
+	resp, err := bs.get("/jobs/" + id)
+	metrics.ObserveBackendCall("GetJobByID", time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job %s: %v", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
+
+	var job Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &job, nil
 }
 
-func (j *jsonDataBuffer) Read(p []byte) (n int, err error) {
-	if len(j.data) == 0 {
-		return 0, fmt.Errorf("no data")
+func (bs *BackendService) GetHealth() (*HealthResponse, error) {
+	start := time.Now()
+	var healthResp HealthResponse
+	err := bs.breaker.callThrough(bs.maxRetries, bs.retryBaseDelay, func() error {
+		resp, err := bs.get("/health")
+		if err != nil {
+			return fmt.Errorf("failed to fetch health status: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("backend returned status %d", resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&healthResp); err != nil {
+			return fmt.Errorf("failed to decode response: %v", err)
+		}
+		return nil
+	})
+	metrics.ObserveBackendCall("GetHealth", time.Since(start), err)
+	if err != nil {
+		return nil, err
 	}
-	n = copy(p, j.data)
-	j.data = j.data[n:]
-	if len(j.data) == 0 {
-		err = fmt.Errorf("EOF")
+	return &healthResp, nil
+}
+
+// writeCircuitOpen reports whether err is a CircuitOpenError and, if so,
+// writes a 503 with a Retry-After header instead of the caller's usual
+// opaque 500.
+func writeCircuitOpen(w http.ResponseWriter, err error) bool {
+	var breakerErr *CircuitOpenError
+	if !errors.As(err, &breakerErr) {
+		return false
 	}
-	return n, nil
+	w.Header().Set("Retry-After", strconv.Itoa(int(breakerErr.RetryAfter.Seconds())+1))
+	http.Error(w, "Backend circuit breaker open", http.StatusServiceUnavailable)
+	return true
 }
 
 // HTTP handlers
 func (bs *BackendService) apiGetJobHandler(w http.ResponseWriter, r *http.Request) {
 	data, err := bs.GetRandomJob()
 	if err != nil {
+		if writeCircuitOpen(w, err) {
+			return
+		}
 		log.Printf("Error fetching random job: %v", err)
 		http.Error(w, "Failed to fetch job from backend", http.StatusInternalServerError)
 		return
@@ -200,8 +434,11 @@ func (bs *BackendService) apiGetJobHandler(w http.ResponseWriter, r *http.Reques
 }
 
 func (bs *BackendService) apiGetJobsHandler(w http.ResponseWriter, r *http.Request) {
-	data, err := bs.GetAllJobs()
+	data, err := bs.GetAllJobs(r.URL.Query().Get("page_state"))
 	if err != nil {
+		if writeCircuitOpen(w, err) {
+			return
+		}
 		log.Printf("Error fetching all jobs: %v", err)
 		http.Error(w, "Failed to fetch jobs from backend", http.StatusInternalServerError)
 		return
@@ -218,32 +455,133 @@ func (bs *BackendService) apiCreateJobHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	data, err := bs.CreateJob(jobData)
+	data, fieldErrors, err := bs.CreateJob(jobData)
 	if err != nil {
+		if writeCircuitOpen(w, err) {
+			return
+		}
 		log.Printf("Error creating job: %v", err)
 		http.Error(w, "Failed to create job", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if len(fieldErrors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(fieldErrors)
+		return
+	}
 	json.NewEncoder(w).Encode(data)
 }
 
+func (bs *BackendService) apiValidateJobHandler(w http.ResponseWriter, r *http.Request) {
+	var jobData map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&jobData); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	fieldErrors, err := bs.ValidateJob(jobData)
+	if err != nil {
+		log.Printf("Error validating job: %v", err)
+		http.Error(w, "Failed to validate job with backend", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(fieldErrors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(fieldErrors)
+}
+
+func (bs *BackendService) apiGetJobByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	job, err := bs.GetJobByID(id)
+	if err != nil {
+		log.Printf("Error fetching job %s: %v", id, err)
+		http.Error(w, "Failed to fetch job from backend", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (bs *BackendService) apiCreateScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Cron    string `json:"cron"`
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	data, err := bs.CreateSchedule(req.Cron, req.Payload)
+	if err != nil {
+		log.Printf("Error creating schedule: %v", err)
+		http.Error(w, "Failed to create schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func (bs *BackendService) apiGetSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := bs.GetSchedules()
+	if err != nil {
+		log.Printf("Error fetching schedules: %v", err)
+		http.Error(w, "Failed to fetch schedules from backend", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func (bs *BackendService) apiDeleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := bs.DeleteSchedule(id); err != nil {
+		log.Printf("Error deleting schedule %s: %v", id, err)
+		http.Error(w, "Failed to delete schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (bs *BackendService) apiHealthHandler(w http.ResponseWriter, r *http.Request) {
 	data, err := bs.GetHealth()
 	if err != nil {
+		if writeCircuitOpen(w, err) {
+			return
+		}
 		log.Printf("Error checking backend health: %v", err)
 		http.Error(w, "Backend service unavailable", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":        data.Status,
+		"pod":           data.Pod,
+		"timestamp":     data.Timestamp,
+		"database":      data.Database,
+		"language":      data.Language,
+		"version":       data.Version,
+		"breaker_state": bs.breaker.State(),
+	})
 }
 
 func (bs *BackendService) apiInfoHandler(w http.ResponseWriter, r *http.Request) {
 	hostname, _ := os.Hostname()
-	
+
 	response := InfoResponse{
 		Service:    "Frontend Application",
 		Language:   "Go",
@@ -257,583 +595,111 @@ func (bs *BackendService) apiInfoHandler(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-func (bs *BackendService) indexHandler(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.New("index").Parse(indexHTML))
-	
-	data := map[string]interface{}{
-		"Title": "Kubernetes Networking Demo - Go Frontend",
-		"Language": "Go",
-		"Framework": "Gorilla Mux",
-	}
-	
-	if err := tmpl.Execute(w, data); err != nil {
-		log.Printf("Error executing template: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-	}
-}
-
-// HTML template
-const indexHTML = `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>{{.Title}}</title>
-    <style>
-        * {
-            margin: 0;
-            padding: 0;
-            box-sizing: border-box;
-        }
-        
-        body {
-            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
-            background: linear-gradient(135deg, #00c9ff 0%, #92fe9d 100%);
-            min-height: 100vh;
-            padding: 20px;
-        }
-        
-        .container {
-            max-width: 1200px;
-            margin: 0 auto;
-            background: white;
-            border-radius: 15px;
-            box-shadow: 0 20px 40px rgba(0,0,0,0.1);
-            overflow: hidden;
-        }
-        
-        .header {
-            background: linear-gradient(135deg, #2c3e50 0%, #34495e 100%);
-            color: white;
-            padding: 30px;
-            text-align: center;
-        }
-        
-        .header h1 {
-            font-size: 2.5em;
-            margin-bottom: 10px;
-            display: flex;
-            align-items: center;
-            justify-content: center;
-            gap: 15px;
-        }
-        
-        .header p {
-            font-size: 1.1em;
-            opacity: 0.9;
-        }
-        
-        .main-content {
-            padding: 40px;
-        }
-        
-        .info-grid {
-            display: grid;
-            grid-template-columns: repeat(auto-fit, minmax(300px, 1fr));
-            gap: 25px;
-            margin-bottom: 30px;
-        }
-        
-        .info-card {
-            background: #f8f9fa;
-            border-radius: 10px;
-            padding: 25px;
-            border-left: 5px solid #00c9ff;
-            transition: transform 0.3s ease, box-shadow 0.3s ease;
-        }
-        
-        .info-card:hover {
-            transform: translateY(-5px);
-            box-shadow: 0 10px 25px rgba(0,0,0,0.1);
-        }
-        
-        .info-card h3 {
-            color: #2c3e50;
-            margin-bottom: 15px;
-            font-size: 1.3em;
-            display: flex;
-            align-items: center;
-            gap: 10px;
-        }
-        
-        .info-item {
-            margin: 10px 0;
-            padding: 8px 0;
-            border-bottom: 1px solid #e9ecef;
-        }
-        
-        .info-item:last-child {
-            border-bottom: none;
-        }
-        
-        .label {
-            font-weight: 600;
-            color: #495057;
-            display: inline-block;
-            min-width: 120px;
-        }
-        
-        .value {
-            color: #00c9ff;
-            font-family: 'Courier New', monospace;
-            background: #e3f2fd;
-            padding: 2px 8px;
-            border-radius: 4px;
-        }
-        
-        .status {
-            display: inline-block;
-            padding: 4px 12px;
-            border-radius: 20px;
-            font-size: 0.85em;
-            font-weight: 600;
-        }
-        
-        .status.healthy {
-            background: #d4edda;
-            color: #155724;
-        }
-        
-        .status.error {
-            background: #f8d7da;
-            color: #721c24;
-        }
-        
-        .loading {
-            text-align: center;
-            padding: 40px;
-            color: #6c757d;
-        }
-        
-        .loading::after {
-            content: '';
-            display: inline-block;
-            width: 20px;
-            height: 20px;
-            border: 3px solid #f3f3f3;
-            border-top: 3px solid #00c9ff;
-            border-radius: 50%;
-            animation: spin 1s linear infinite;
-            margin-left: 10px;
-        }
-        
-        @keyframes spin {
-            0% { transform: rotate(0deg); }
-            100% { transform: rotate(360deg); }
-        }
-        
-        .button-group {
-            display: flex;
-            gap: 15px;
-            justify-content: center;
-            margin: 30px 0;
-            flex-wrap: wrap;
-        }
-        
-        .btn {
-            background: linear-gradient(135deg, #00c9ff 0%, #92fe9d 100%);
-            color: white;
-            border: none;
-            padding: 12px 25px;
-            border-radius: 8px;
-            cursor: pointer;
-            font-size: 1em;
-            font-weight: 600;
-            transition: all 0.3s ease;
-            display: flex;
-            align-items: center;
-            gap: 8px;
-        }
-        
-        .btn:hover {
-            transform: translateY(-2px);
-            box-shadow: 0 5px 15px rgba(0,201,255,0.3);
-        }
-        
-        .btn.secondary {
-            background: linear-gradient(135deg, #95a5a6 0%, #7f8c8d 100%);
-        }
-        
-        .btn.success {
-            background: linear-gradient(135deg, #27ae60 0%, #229954 100%);
-        }
-        
-        .jobs-section {
-            margin-top: 40px;
-            padding: 30px;
-            background: #f8f9fa;
-            border-radius: 10px;
-        }
-        
-        .jobs-grid {
-            display: grid;
-            grid-template-columns: repeat(auto-fill, minmax(300px, 1fr));
-            gap: 20px;
-            margin-top: 20px;
-        }
-        
-        .job-card {
-            background: white;
-            border-radius: 8px;
-            padding: 20px;
-            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
-            border-left: 4px solid #27ae60;
-        }
-        
-        .job-title {
-            font-weight: 600;
-            color: #2c3e50;
-            margin-bottom: 10px;
-        }
-        
-        .job-description {
-            color: #6c757d;
-            margin-bottom: 15px;
-        }
-        
-        .job-meta {
-            display: flex;
-            justify-content: space-between;
-            font-size: 0.9em;
-        }
-        
-        .error-message {
-            background: #f8d7da;
-            color: #721c24;
-            padding: 15px;
-            border-radius: 8px;
-            margin: 20px 0;
-            border-left: 4px solid #dc3545;
-        }
-        
-        .footer {
-            background: #2c3e50;
-            color: white;
-            text-align: center;
-            padding: 20px;
-            margin-top: 40px;
-        }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <div class="header">
-            <h1>
-                üöÄ Kubernetes Networking Demo
-                <span style="font-size: 0.6em; background: #e74c3c; padding: 5px 10px; border-radius: 15px;">Go Stack</span>
-            </h1>
-            <p>Multi-tier application demonstrating Kubernetes Services with Go + Gorilla Mux + Cassandra</p>
-        </div>
-        
-        <div class="main-content">
-            <div id="loading" class="loading">Loading application data...</div>
-            <div id="error" class="error-message" style="display: none;"></div>
-            
-            <div id="content" style="display: none;">
-                <div class="info-grid">
-                    <div class="info-card">
-                        <h3>üéØ Current Job</h3>
-                        <div id="job-info">
-                            <div class="info-item">
-                                <span class="label">Title:</span>
-                                <span class="value" id="job-title">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Description:</span>
-                                <span class="value" id="job-description">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Status:</span>
-                                <span class="value" id="job-status">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Priority:</span>
-                                <span class="value" id="job-priority">-</span>
-                            </div>
-                        </div>
-                    </div>
-                    
-                    <div class="info-card">
-                        <h3>üîß Backend Service</h3>
-                        <div id="backend-info">
-                            <div class="info-item">
-                                <span class="label">Pod:</span>
-                                <span class="value" id="backend-pod">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Pod IP:</span>
-                                <span class="value" id="backend-ip">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Database:</span>
-                                <span class="value" id="database-type">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Language:</span>
-                                <span class="value" id="backend-language">-</span>
-                            </div>
-                        </div>
-                    </div>
-                    
-                    <div class="info-card">
-                        <h3>üåê Frontend Service</h3>
-                        <div id="frontend-info">
-                            <div class="info-item">
-                                <span class="label">Pod:</span>
-                                <span class="value" id="frontend-pod">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Client IP:</span>
-                                <span class="value" id="client-ip">-</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Framework:</span>
-                                <span class="value" id="frontend-framework">{{.Framework}}</span>
-                            </div>
-                            <div class="info-item">
-                                <span class="label">Health Status:</span>
-                                <span class="status" id="health-status">-</span>
-                            </div>
-                        </div>
-                    </div>
-                </div>
-                
-                <div class="button-group">
-                    <button class="btn" onclick="refreshData()">
-                        üîÑ Refresh Data
-                    </button>
-                    <button class="btn secondary" onclick="showAllJobs()">
-                        üìã View All Jobs
-                    </button>
-                    <button class="btn success" onclick="createNewJob()">
-                        ‚ûï Create New Job
-                    </button>
-                </div>
-                
-                <div id="jobs-section" class="jobs-section" style="display: none;">
-                    <h3>üìä All Jobs in Database</h3>
-                    <div id="jobs-grid" class="jobs-grid"></div>
-                </div>
-            </div>
-        </div>
-        
-        <div class="footer">
-            <p>Kubernetes Networking Lab | Go + Gorilla Mux + Cassandra | Multi-Language Demo</p>
-        </div>
-    </div>
-
-    <script>
-        let currentData = {};
-        
-        async function fetchBackendData() {
-            try {
-                const response = await fetch('/api/job');
-                if (!response.ok) throw new Error(\`HTTP \${response.status}: \${response.statusText}\`);
-                return await response.json();
-            } catch (error) {
-                console.error('Error fetching backend data:', error);
-                throw error;
-            }
-        }
-        
-        async function fetchAllJobs() {
-            try {
-                const response = await fetch('/api/jobs');
-                if (!response.ok) throw new Error(\`HTTP \${response.status}: \${response.statusText}\`);
-                return await response.json();
-            } catch (error) {
-                console.error('Error fetching all jobs:', error);
-                throw error;
-            }
-        }
-        
-        async function fetchHealthStatus() {
-            try {
-                const response = await fetch('/api/health');
-                if (!response.ok) throw new Error(\`HTTP \${response.status}: \${response.statusText}\`);
-                return await response.json();
-            } catch (error) {
-                console.error('Error fetching health status:', error);
-                throw error;
-            }
-        }
-        
-        function updateJobInfo(job) {
-            if (job && job.title) {
-                document.getElementById('job-title').textContent = job.title;
-                document.getElementById('job-description').textContent = job.description || 'No description';
-                document.getElementById('job-status').textContent = job.status || 'unknown';
-                document.getElementById('job-priority').textContent = job.priority || 'N/A';
-            } else {
-                document.getElementById('job-title').textContent = 'No jobs available';
-                document.getElementById('job-description').textContent = 'Database is empty';
-                document.getElementById('job-status').textContent = 'N/A';
-                document.getElementById('job-priority').textContent = 'N/A';
-            }
-        }
-        
-        function updateBackendInfo(data) {
-            document.getElementById('backend-pod').textContent = data.pod || 'Unknown';
-            document.getElementById('backend-ip').textContent = data.podIP || 'Unknown';
-            document.getElementById('database-type').textContent = data.database || 'Unknown';
-            document.getElementById('backend-language').textContent = data.cluster_info?.language || 'Unknown';
-        }
-        
-        function updateFrontendInfo() {
-            document.getElementById('frontend-pod').textContent = 'Frontend Pod';
-            document.getElementById('client-ip').textContent = window.location.hostname;
-        }
-        
-        function updateHealthStatus(health) {
-            const statusElement = document.getElementById('health-status');
-            if (health.status === 'healthy') {
-                statusElement.textContent = '‚úÖ Healthy';
-                statusElement.className = 'status healthy';
-            } else {
-                statusElement.textContent = '‚ùå Unhealthy';
-                statusElement.className = 'status error';
-            }
-        }
-        
-        function displayAllJobs(jobsData) {
-            const jobsSection = document.getElementById('jobs-section');
-            const jobsGrid = document.getElementById('jobs-grid');
-            
-            if (!jobsData.jobs || jobsData.jobs.length === 0) {
-                jobsGrid.innerHTML = '<p>No jobs found in database.</p>';
-            } else {
-                jobsGrid.innerHTML = jobsData.jobs.map(job => \`
-                    <div class="job-card">
-                        <div class="job-title">\${job.title}</div>
-                        <div class="job-description">\${job.description || 'No description'}</div>
-                        <div class="job-meta">
-                            <span>Status: <strong>\${job.status || 'unknown'}</strong></span>
-                            <span>Priority: <strong>\${job.priority || 'N/A'}</strong></span>
-                        </div>
-                    </div>
-                \`).join('');
-            }
-            
-            jobsSection.style.display = 'block';
-        }
-        
-        async function refreshData() {
-            const loading = document.getElementById('loading');
-            const content = document.getElementById('content');
-            const error = document.getElementById('error');
-            
-            loading.style.display = 'block';
-            content.style.display = 'none';
-            error.style.display = 'none';
-            
-            try {
-                // Fetch all data in parallel
-                const [backendData, allJobs, healthStatus] = await Promise.all([
-                    fetchBackendData(),
-                    fetchAllJobs(),
-                    fetchHealthStatus()
-                ]);
-                
-                currentData = { backendData, allJobs, healthStatus };
-                
-                // Update UI
-                updateJobInfo(backendData.job);
-                updateBackendInfo(backendData);
-                updateFrontendInfo();
-                updateHealthStatus(healthStatus);
-                
-                loading.style.display = 'none';
-                content.style.display = 'block';
-                
-            } catch (error) {
-                loading.style.display = 'none';
-                error.style.display = 'block';
-                error.innerHTML = \`
-                    <strong>Error:</strong> \${error.message}<br>
-                    <small>Please check if backend service is running and accessible.</small>
-                \`;
-            }
-        }
-        
-        function showAllJobs() {
-            if (currentData.allJobs) {
-                displayAllJobs(currentData.allJobs);
-            } else {
-                alert('Please refresh data first');
-            }
-        }
-        
-        function createNewJob() {
-            const title = prompt('Enter job title:');
-            if (!title) return;
-            
-            const description = prompt('Enter job description:');
-            if (!description) return;
-            
-            const status = prompt('Enter job status (pending/in_progress/completed):', 'pending');
-            const priority = prompt('Enter job priority (1-5):', '1');
-            
-            fetch('/api/jobs', {
-                method: 'POST',
-                headers: {
-                    'Content-Type': 'application/json',
-                },
-                body: JSON.stringify({
-                    title,
-                    description,
-                    status: status || 'pending',
-                    priority: parseInt(priority) || 1
-                })
-            })
-            .then(response => response.json())
-            .then(data => {
-                alert('Job created successfully!');
-                refreshData();
-            })
-            .catch(error => {
-                alert('Error creating job: ' + error.message);
-            });
-        }
-        
-        // Initialize on page load
-        document.addEventListener('DOMContentLoaded', refreshData);
-        
-        // Auto-refresh every 30 seconds
-        setInterval(refreshData, 30000);
-    </script>
-</body>
-</html>`
-
 func main() {
+	breakerThreshold, err := strconv.ParseFloat(getEnv("BREAKER_THRESHOLD", "0.5"), 64)
+	if err != nil {
+		log.Fatalf("Invalid BREAKER_THRESHOLD: %v", err)
+	}
+
+	breakerWindow, err := time.ParseDuration(getEnv("BREAKER_WINDOW", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid BREAKER_WINDOW: %v", err)
+	}
+
+	maxRetries, err := strconv.Atoi(getEnv("MAX_RETRIES", "2"))
+	if err != nil {
+		log.Fatalf("Invalid MAX_RETRIES: %v", err)
+	}
+
+	retryBaseDelay, err := time.ParseDuration(getEnv("RETRY_BASE_DELAY", "100ms"))
+	if err != nil {
+		log.Fatalf("Invalid RETRY_BASE_DELAY: %v", err)
+	}
+
 	config := &Config{
 		BackendURL: getEnv("BACKEND_URL", "http://backend-service:5000"),
 		Port:       getEnv("PORT", "8080"),
+
+		BreakerThreshold: breakerThreshold,
+		BreakerWindow:    breakerWindow,
+		MaxRetries:       maxRetries,
+		RetryBaseDelay:   retryBaseDelay,
+
+		ServiceName:  getEnv("SERVICE_NAME", "frontend-go"),
+		OTelEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4318"),
+
+		CORSAllowedOrigins: parseOrigins(getEnv("CORS_ALLOWED_ORIGINS", "")),
+	}
+
+	shutdownTracer, err := initTracer(context.Background(), config.ServiceName, config.OTelEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize OTel tracer: %v", err)
 	}
+	defer func() {
+		if err := shutdownTracer(context.Background()); err != nil {
+			log.Printf("Error shutting down tracer: %v", err)
+		}
+	}()
+
+	// shutdownCtx is cancelled on SIGTERM, before server.Shutdown starts
+	// draining, so in-flight upstream BackendService calls fail fast
+	// instead of blocking the drain until their own timeout elapses.
+	shutdownCtx, cancelShutdown := context.WithCancel(context.Background())
+	defer cancelShutdown()
 
 	// Initialize backend service
-	backendService := NewBackendService(config.BackendURL)
+	backendService := NewBackendService(shutdownCtx, config.BackendURL, config.BreakerThreshold, config.BreakerWindow, config.MaxRetries, config.RetryBaseDelay)
+
+	// Live job updates: poll the backend and fan out diffs over SSE
+	jobEventBroker := NewJobEventBroker()
+	pollCtx, cancelPoll := context.WithCancel(shutdownCtx)
+	defer cancelPoll()
+	go pollAndBroadcast(pollCtx, backendService, jobEventBroker, 5*time.Second)
 
 	// Setup router
 	router := mux.NewRouter()
-	
+	router.Use(metrics.Middleware)
+
+	// Metrics
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	// API routes
 	router.HandleFunc("/api/job", backendService.apiGetJobHandler).Methods("GET")
 	router.HandleFunc("/api/jobs", backendService.apiGetJobsHandler).Methods("GET")
 	router.HandleFunc("/api/jobs", backendService.apiCreateJobHandler).Methods("POST")
+	router.HandleFunc("/api/jobs/validate", backendService.apiValidateJobHandler).Methods("POST")
+	router.HandleFunc("/api/jobs/stream", jobEventBroker.streamHandler).Methods("GET")
+	router.HandleFunc("/api/jobs/{id}", backendService.apiGetJobByIDHandler).Methods("GET")
+	router.HandleFunc("/api/schedules", backendService.apiCreateScheduleHandler).Methods("POST")
+	router.HandleFunc("/api/schedules", backendService.apiGetSchedulesHandler).Methods("GET")
+	router.HandleFunc("/api/schedules/{id}", backendService.apiDeleteScheduleHandler).Methods("DELETE")
 	router.HandleFunc("/api/health", backendService.apiHealthHandler).Methods("GET")
 	router.HandleFunc("/api/info", backendService.apiInfoHandler).Methods("GET")
-	
+
 	// Main page
 	router.HandleFunc("/", backendService.indexHandler).Methods("GET")
+	router.PathPrefix("/assets/").Handler(assetsHandler()).Methods("GET")
 
-	// Setup CORS
-	c := cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
+	// Setup CORS. rs/cors treats an empty AllowedOrigins (with no
+	// AllowOriginFunc) as "allow all", so an empty allow-list must instead
+	// set an AllowOriginFunc that rejects every origin to actually grant no
+	// cross-origin access; only same-origin callers can reach the API.
+	corsOptions := cors.Options{
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{"*"},
-	})
+	}
+	if len(config.CORSAllowedOrigins) > 0 {
+		corsOptions.AllowedOrigins = config.CORSAllowedOrigins
+	} else {
+		corsOptions.AllowOriginFunc = func(origin string) bool { return false }
+	}
+	c := cors.New(corsOptions)
 
-	handler := c.Handler(router)
+	// CSRF protection for mutating requests (POST/PUT/DELETE/PATCH): nosurf
+	// rejects them unless the X-CSRF-Token header matches the token issued
+	// via the cookie it sets, which indexHandler exposes to the page as a
+	// <meta name="csrf-token"> tag.
+	handler := c.Handler(otelhttp.NewHandler(nosurf.New(router), config.ServiceName))
 
 	// Start server
 	server := &http.Server{
@@ -844,13 +710,26 @@ func main() {
 	log.Printf("Starting Go frontend server on port %s", config.Port)
 	log.Printf("Backend URL: %s", config.BackendURL)
 
-	// Graceful shutdown
+	// Bind explicitly (rather than via ListenAndServe) so READY=1 is sent
+	// only once the port is actually listening, matching the Podman API
+	// server's startup pattern for k3s readiness/liveness probes.
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("Failed to bind %s: %v", server.Addr, err)
+	}
+
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
 
+	if sent, err := daemon.SdNotify(false, daemon.SdNotifyReady); err != nil {
+		log.Printf("Error notifying systemd readiness: %v", err)
+	} else if sent {
+		log.Println("Notified systemd: READY=1")
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -858,6 +737,16 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	if sent, err := daemon.SdNotify(false, daemon.SdNotifyStopping); err != nil {
+		log.Printf("Error notifying systemd stopping: %v", err)
+	} else if sent {
+		log.Println("Notified systemd: STOPPING=1")
+	}
+
+	// Cancel in-flight upstream calls before draining so they fail fast
+	// rather than holding the drain open until their own timeout.
+	cancelShutdown()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -873,4 +762,19 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// parseOrigins splits a comma-separated CORS_ALLOWED_ORIGINS value into a
+// trimmed, non-empty origin list. An empty input yields an empty (non-nil)
+// slice; main then rejects every origin via AllowOriginFunc, since rs/cors
+// alone would treat an empty list as "allow all".
+func parseOrigins(value string) []string {
+	origins := []string{}
+	for _, origin := range strings.Split(value, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,105 @@
+// Package metrics exposes Prometheus instrumentation for the frontend: an
+// HTTP middleware that records per-route request counts, latency, and
+// in-flight concurrency, plus counters the BackendService client calls
+// directly to track upstream call latency and errors by method.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "frontend_http_requests_total",
+		Help: "Total HTTP requests handled by the frontend, by route and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "frontend_http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "frontend_http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served by the frontend.",
+	})
+
+	backendCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "frontend_backend_call_duration_seconds",
+		Help:    "Latency of calls from the frontend to the backend, by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"call"})
+
+	backendCallErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "frontend_backend_call_errors_total",
+		Help: "Total failed calls from the frontend to the backend, by method.",
+	}, []string{"call"})
+)
+
+// Handler serves the Prometheus text exposition format for scraping.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// statusRecorder captures the status code a handler wrote, since
+// http.ResponseWriter doesn't expose it after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush forwards to the wrapped writer so streaming handlers (the SSE job
+// stream) still see an http.Flusher behind this middleware.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Middleware records request counts, latency, and in-flight concurrency for
+// every request the router dispatches. It's meant to be installed with
+// router.Use so mux.CurrentRoute is already resolved by the time it runs.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		duration := time.Since(start)
+
+		httpRequestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+		httpRequestDuration.WithLabelValues(route, r.Method).Observe(duration.Seconds())
+	})
+}
+
+// ObserveBackendCall records the latency and, if err is non-nil, a failure
+// for an upstream BackendService call identified by name (e.g.
+// "GetRandomJob", "CreateJob").
+func ObserveBackendCall(call string, duration time.Duration, err error) {
+	backendCallDuration.WithLabelValues(call).Observe(duration.Seconds())
+	if err != nil {
+		backendCallErrors.WithLabelValues(call).Inc()
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JobEvent is a single SSE frame pushed to connected browsers when the
+// poller notices a job was created or updated upstream.
+type JobEvent struct {
+	Type string `json:"type"`
+	Job  Job    `json:"job"`
+}
+
+const (
+	subscriberBuffer  = 16
+	keepaliveInterval = 15 * time.Second
+)
+
+// JobEventBroker fans out JobEvents to every connected SSE client. Each
+// client gets its own bounded channel; a client that falls behind is
+// dropped rather than blocking the broadcaster.
+type JobEventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan JobEvent]struct{}
+}
+
+func NewJobEventBroker() *JobEventBroker {
+	return &JobEventBroker{
+		subscribers: make(map[chan JobEvent]struct{}),
+	}
+}
+
+func (b *JobEventBroker) subscribe() chan JobEvent {
+	ch := make(chan JobEvent, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *JobEventBroker) unsubscribe(ch chan JobEvent) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+	close(ch)
+}
+
+// Publish delivers an event to every subscriber, evicting (and closing) any
+// subscriber whose buffer is full instead of blocking on a slow consumer.
+func (b *JobEventBroker) Publish(event JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Evicting slow SSE subscriber")
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// streamHandler serves GET /api/jobs/stream, pushing job.created/job.updated
+// events as they're published and a periodic heartbeat comment to keep
+// intermediaries from closing an idle connection.
+func (b *JobEventBroker) streamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(event.Job)
+			if err != nil {
+				log.Printf("Error marshaling job event: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// fetchAllJobs follows GetAllJobs' NextPageState cursor until it's
+// exhausted, so callers that diff the full job list (the SSE poller) don't
+// go blind once the table grows past one page.
+func fetchAllJobs(backend *BackendService) ([]Job, error) {
+	var jobs []Job
+	pageState := ""
+	for {
+		page, err := backend.GetAllJobs(pageState)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Jobs...)
+		if page.NextPageState == "" {
+			return jobs, nil
+		}
+		pageState = page.NextPageState
+	}
+}
+
+// pollAndBroadcast polls the backend's job list on an interval, diffs it
+// against the previous snapshot, and publishes job.created/job.updated
+// events for anything new or changed. It returns when ctx is cancelled.
+func pollAndBroadcast(ctx context.Context, backend *BackendService, broker *JobEventBroker, interval time.Duration) {
+	seen := make(map[string]time.Time)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobs, err := fetchAllJobs(backend)
+			if err != nil {
+				log.Printf("SSE poller: failed to fetch jobs: %v", err)
+				continue
+			}
+
+			for _, job := range jobs {
+				lastSeen, known := seen[job.ID]
+				seen[job.ID] = job.UpdatedAt
+
+				switch {
+				case !known:
+					broker.Publish(JobEvent{Type: "job.created", Job: job})
+				case job.UpdatedAt.After(lastSeen):
+					broker.Publish(JobEvent{Type: "job.updated", Job: job})
+				}
+			}
+		}
+	}
+}
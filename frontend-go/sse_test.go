@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestFetchAllJobsPaginates checks that fetchAllJobs follows NextPageState
+// across multiple pages instead of stopping after the backend's first page.
+func TestFetchAllJobsPaginates(t *testing.T) {
+	pages := map[string]JobsResponse{
+		"": {
+			Jobs:          []Job{{ID: "1"}, {ID: "2"}},
+			NextPageState: "page-2",
+		},
+		"page-2": {
+			Jobs:          []Job{{ID: "3"}},
+			NextPageState: "",
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := pages[r.URL.Query().Get("page_state")]
+		if !ok {
+			http.Error(w, "unexpected page_state", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	backend := NewBackendService(context.Background(), server.URL, 0.5, time.Minute, 0, time.Millisecond)
+
+	jobs, err := fetchAllJobs(backend)
+	if err != nil {
+		t.Fatalf("fetchAllJobs failed: %v", err)
+	}
+
+	if len(jobs) != 3 {
+		t.Fatalf("fetchAllJobs returned %d jobs, want 3", len(jobs))
+	}
+	for i, wantID := range []string{"1", "2", "3"} {
+		if jobs[i].ID != wantID {
+			t.Errorf("jobs[%d].ID = %q, want %q", i, jobs[i].ID, wantID)
+		}
+	}
+}